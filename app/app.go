@@ -1,65 +1,275 @@
 package app
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"os/exec"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shadyabhi/mantee/history"
+	"github.com/shadyabhi/mantee/man"
 	"github.com/shadyabhi/mantee/man/parse"
 	"github.com/shadyabhi/mantee/man/search"
 	searchui "github.com/shadyabhi/mantee/search"
 	"github.com/shadyabhi/mantee/viewer"
 )
 
-// Run orchestrates the two-stage UI flow: search/selection → viewer
-func Run(keyword string) error {
-	var model searchui.Model
+// maxRecentPages is how many recently opened pages the input screen lists
+const maxRecentPages = 10
+
+// openHistory returns the history store at its default location, or nil if
+// the location cannot be determined
+func openHistory() *history.Store {
+	path, err := history.DefaultPath()
+	if err != nil {
+		return nil
+	}
+	return history.Open(path)
+}
+
+// Options configures a run of the application
+type Options struct {
+	Width        int                  // Fixed MANWIDTH for fetched pages (0 for the default)
+	Col          parse.ColMode        // How tabs in man output are handled
+	Plain        bool                 // Strip man's formatting with col instead of rendering it
+	SqueezeBlank bool                 // Collapse runs of 3+ blank lines into one
+	Search       search.SearchOptions // How search keywords are matched
+	Env          man.Env              // Environment overrides (MANPATH, LANG) for every man invocation
+	ManArgs      []string             // Extra arguments for man when fetching pages
+
+	DefaultSearch  string // Search type "/" starts in the viewer
+	ConfirmQuit    bool   // Ask before q quits the viewer with a search or jumps in progress
+	ScrollStep     int    // Lines j/k move in the viewer (1 when zero)
+	ScrollAccel    bool   // Grow the viewer's j/k step while the key is held
+	CenterCursor   bool   // Keep the viewer's cursor centered while moving
+	ScrollOff      int    // Context lines kept around the viewer's cursor
+	ClearSearchTop bool   // Return to the top when esc clears a search in the viewer
+	OpenWithMan    bool   // Hand the selected page to man itself instead of the viewer
+	StartupPage    string // Page opened without a keyword, as "[section] name" ("" for the search prompt)
+	Columns        bool   // List search results as a grid of name(section) entries
+	Inline         bool   // Run the viewer without the alt screen, keeping the page in scrollback
+
+	CurrentMatchColor string // Viewer highlight for the current search occurrence ("" for the default)
+	MatchColor        string // Viewer background for other matching lines ("" for the default)
+	FocusColor        string // Border color of the viewer's focused pane ("" for the default)
+	FocusBorder       string // Border style of the viewer's focused pane ("" for the default)
+
+	PaneOrder     []string // Panes tab cycles through in the viewer (nil for all)
+	CenterContent bool     // Center pages much narrower than the viewer's content pane
+	SectionRules  bool     // Draw a rule after each man section header in the viewer
+	EnterAction   string   // What enter does in the viewer's content pane ("" for follow)
+	SmartCase     bool     // Match viewer searches with an uppercase letter case-sensitively
+}
+
+// fetchOptions returns the options pages are fetched with
+func (o Options) fetchOptions() parse.FetchOptions {
+	return parse.FetchOptions{
+		Width:        o.Width,
+		Col:          o.Col,
+		Plain:        o.Plain,
+		Env:          o.Env,
+		SqueezeBlank: o.SqueezeBlank,
+		ManArgs:      o.ManArgs,
+	}
+}
 
+// Run orchestrates the two-stage UI flow: search/selection → viewer. Without
+// a keyword, the configured startup page opens first. ctrl+s in the viewer
+// returns to the search prompt.
+func Run(keyword string, opts Options) error {
+	opts.Search.Env = opts.Env
+	store := openHistory()
+
+	if keyword == "" && opts.StartupPage != "" {
+		page, ok := pageFromArgs(strings.Fields(opts.StartupPage))
+		if !ok {
+			return fmt.Errorf("invalid startup page %q: want [section] name", opts.StartupPage)
+		}
+		content, err := parse.FetchManPage(page.Section, page.Name, opts.fetchOptions())
+		if err != nil {
+			return fmt.Errorf("fetching startup page: %w", err)
+		}
+		back, err := viewPage(page, content, nil, 0, store, opts)
+		if err != nil || !back {
+			return err
+		}
+	}
+
+	for {
+		back, err := searchAndView(keyword, store, opts)
+		if err != nil || !back {
+			return err
+		}
+		// Back from the viewer: start over at an empty prompt
+		keyword = ""
+	}
+}
+
+// searchAndView runs the search/selection UI, then views the selected page.
+// It reports whether the viewer was left for the search prompt.
+func searchAndView(keyword string, store *history.Store, opts Options) (back bool, err error) {
+	var model searchui.Model
 	if keyword != "" {
 		// Keyword provided - search and go directly to selection
-		pages, err := search.SearchManPages(keyword)
+		pages, total, err := search.SearchManPages(keyword, opts.Search)
 		if err != nil {
-			return fmt.Errorf("searching man pages: %w", err)
+			return false, fmt.Errorf("searching man pages: %w", err)
 		}
 
 		if len(pages) == 0 {
-			return fmt.Errorf("no man pages found for: %s", keyword)
+			// Let the user edit the keyword and retry rather than exiting
+			model = searchui.New(opts.Search).WithNoResults(keyword)
+		} else {
+			model = searchui.NewWithResults(keyword, pages, total, opts.Search)
 		}
-
-		model = searchui.NewWithResults(keyword, pages)
 	} else {
-		// No keyword - start with text input
-		model = searchui.New()
+		// No keyword - start with text input, offering recently opened pages
+		model = searchui.New(opts.Search)
+		if store != nil {
+			if recent, err := store.Recent(maxRecentPages); err == nil {
+				model = model.WithRecent(recent)
+			}
+		}
 	}
+	if store != nil {
+		model = model.WithFavorites(store)
+	}
+	model = model.WithColumns(opts.Columns)
 
-	// Run the search/selection UI
-	p := tea.NewProgram(model)
-	finalModel, err := p.Run()
-	if err != nil {
-		return fmt.Errorf("running search UI: %w", err)
+	var m searchui.Model
+	var selected *search.ManPage
+	var content *parse.ManPageContent
+	for {
+		// Run the search/selection UI
+		p := tea.NewProgram(model)
+		finalModel, err := p.Run()
+		if err != nil {
+			return false, fmt.Errorf("running search UI: %w", err)
+		}
+
+		// Check if a page was selected
+		m = finalModel.(searchui.Model)
+		selected = m.Selected()
+		if selected == nil {
+			// User quit without selecting
+			return false, nil
+		}
+
+		if opts.OpenWithMan {
+			if store != nil {
+				_ = store.Record(*selected)
+			}
+			return false, openWithMan(*selected, opts.Env)
+		}
+
+		// Fetch the man page content
+		content, err = parse.FetchManPage(selected.Section, selected.Name, opts.fetchOptions())
+		if errors.Is(err, parse.ErrNoManualEntry) {
+			// E.g. a recent page since uninstalled: offer similar pages
+			// rather than exiting
+			model = m.WithNotFound(*selected, suggestPages(selected.Name, opts.Search))
+			continue
+		}
+		if err != nil {
+			return false, fmt.Errorf("fetching man page: %w", err)
+		}
+		break
 	}
 
-	// Check if a page was selected
-	m := finalModel.(searchui.Model)
-	selected := m.Selected()
-	if selected == nil {
-		// User quit without selecting
-		return nil
+	// Remember the page for the recent list; failure to persist is not fatal
+	if store != nil {
+		_ = store.Record(*selected)
 	}
 
-	// Fetch the man page content
-	content, err := parse.FetchManPage(selected.Section, selected.Name)
+	results, index := m.Results()
+	return viewPage(*selected, content, results, index, store, opts)
+}
+
+// viewPage runs the viewer on page, with the search results it was picked
+// from (if any) for ctrl+n/ctrl+p. It reports whether the viewer was left
+// for the search prompt.
+func viewPage(page search.ManPage, content *parse.ManPageContent, results []search.ManPage, index int, store *history.Store, opts Options) (back bool, err error) {
+	vopts := opts.viewerOptions(store)
+	vopts.Results, vopts.ResultIndex = results, index
+	vopts.SearchPrompt = true
+	v, err := runViewer(viewer.New(page, content, vopts), opts.Inline)
+	return v.BackToSearch(), err
+}
+
+// suggestPages returns pages whose name or description mentions name, for
+// when man has no page by that name. Errors just mean no suggestions.
+func suggestPages(name string, opts search.SearchOptions) []search.ManPage {
+	opts.Mode = search.MatchKeyword // The name is not a pattern
+	pages, _, err := search.SearchManPages(name, opts)
 	if err != nil {
-		return fmt.Errorf("fetching man page: %w", err)
+		return nil
 	}
+	return pages
+}
 
-	// Launch the viewer
-	v := viewer.New(*selected, content)
-	viewerProgram := tea.NewProgram(v, tea.WithAltScreen(), tea.WithMouseCellMotion())
+// viewerOptions returns the options the viewer is started with
+func (o Options) viewerOptions(store *history.Store) viewer.Options {
+	return viewer.Options{
+		Fetch:          o.fetchOptions(),
+		DefaultSearch:  o.DefaultSearch,
+		ConfirmQuit:    o.ConfirmQuit,
+		ScrollStep:     o.ScrollStep,
+		ScrollAccel:    o.ScrollAccel,
+		CenterCursor:   o.CenterCursor,
+		ScrollOff:      o.ScrollOff,
+		ClearSearchTop: o.ClearSearchTop,
+		Favorites:      store,
+		Inline:         o.Inline,
 
-	_, err = viewerProgram.Run()
+		CurrentMatchColor: o.CurrentMatchColor,
+		MatchColor:        o.MatchColor,
+		FocusColor:        o.FocusColor,
+		FocusBorder:       o.FocusBorder,
+		PaneOrder:         o.PaneOrder,
+		CenterContent:     o.CenterContent,
+		SectionRules:      o.SectionRules,
+		EnterAction:       o.EnterAction,
+		SmartCase:         o.SmartCase,
+	}
+}
+
+// runViewer runs the viewer until it quits and returns its final state
+func runViewer(v viewer.Viewer, inline bool, extra ...tea.ProgramOption) (viewer.Viewer, error) {
+	programOpts := []tea.ProgramOption{tea.WithAltScreen(), tea.WithMouseCellMotion()}
+	if inline {
+		// Mouse coordinates are only relative to the view in the alt screen,
+		// so clicks can't be mapped to panes inline
+		programOpts = nil
+	}
+	programOpts = append(programOpts, extra...)
+	final, err := tea.NewProgram(v, programOpts...).Run()
 	if err != nil {
-		return fmt.Errorf("running viewer: %w", err)
+		return v, fmt.Errorf("running viewer: %w", err)
+	}
+	fv, ok := final.(viewer.Viewer)
+	if !ok {
+		return v, fmt.Errorf("running viewer: unexpected final model %T", final)
 	}
+	return fv, nil
+}
 
+// openWithMan runs man on page attached to the terminal, so it opens in the
+// user's usual pager, and waits for it to exit
+func openWithMan(page search.ManPage, env man.Env) error {
+	args := []string{page.Name}
+	if page.Section != "" {
+		args = []string{page.Section, page.Name}
+	}
+	argv := env.Command(nil, args...)
+	cmd := exec.Command(argv[0], argv[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	env.Apply(cmd)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("running %s: %w", page.Command(), err)
+	}
 	return nil
 }
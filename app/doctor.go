@@ -0,0 +1,101 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+
+	"github.com/shadyabhi/mantee/man/parse"
+	"github.com/shadyabhi/mantee/man/search"
+)
+
+// doctorCheck is one line of the --doctor report
+type doctorCheck struct {
+	name     string
+	err      error  // Nil when the check passed
+	detail   string // What was found, or why the check was skipped
+	hint     string // How to fix a failure
+	critical bool   // Whether mantee can't work without it
+	skipped  bool   // Not run; detail says why
+}
+
+// Doctor checks the external commands and man setup mantee relies on and
+// prints what is missing with hints to fix it. It returns an error when a
+// critical check fails, so the exit status reflects it.
+func Doctor(w io.Writer, opts Options) error {
+	opts.Search.Env = opts.Env
+	var checks []doctorCheck
+
+	if len(opts.Env.Exec) > 0 {
+		// man itself runs on the other side and is checked by fetching a page
+		checks = append(checks, lookPathCheck(opts.Env.Exec[0], "needed by --exec", true))
+	} else {
+		checks = append(checks, lookPathCheck("man", "install man-db (Linux) or mandoc", true))
+	}
+	manFound := checks[0].err == nil
+	checks = append(checks,
+		lookPathCheck("sh", "a POSIX shell runs man with MANWIDTH set", true),
+		lookPathCheck("col", "install bsdextrautils or util-linux (only needed for --plain and --expand-tabs)", opts.Plain),
+	)
+
+	apropos := doctorCheck{
+		name:   "man -k",
+		detail: "finds pages",
+		hint:   "build the whatis database with mandb (Linux) or makewhatis (macOS/BSD); until then mantee scans MANPATH for page names",
+	}
+	switch {
+	case !manFound:
+		apropos.skipped, apropos.detail = true, "needs man"
+	case opts.Search.Index:
+		apropos.skipped, apropos.detail = true, "not used with --index"
+	default:
+		apropos.err = search.CheckApropos(opts.Search)
+	}
+	checks = append(checks, apropos)
+
+	page := doctorCheck{
+		name:     "man 1 man",
+		hint:     "man pages may be missing, e.g. stripped from a minimal container image (run unminimize on Ubuntu, or install man-pages)",
+		critical: true,
+	}
+	if !manFound {
+		page.skipped, page.detail = true, "needs man"
+	} else if content, err := parse.FetchManPage("1", "man", opts.fetchOptions()); err != nil {
+		page.err = err
+	} else if len(content.ManSections) == 0 {
+		page.err = errors.New("the page has no sections")
+		page.hint = "man's output wasn't recognized; please report it with --debug-parse man"
+	} else {
+		page.detail = fmt.Sprintf("%d lines, %d sections, %d options", len(content.Lines), len(content.ManSections), len(content.Sections))
+	}
+	checks = append(checks, page)
+
+	failed := 0
+	for _, c := range checks {
+		switch {
+		case c.skipped:
+			fmt.Fprintf(w, "skip  %s: %s\n", c.name, c.detail)
+			continue
+		case c.err == nil:
+			fmt.Fprintf(w, "ok    %s: %s\n", c.name, c.detail)
+			continue
+		case c.critical:
+			failed++
+			fmt.Fprintf(w, "FAIL  %s: %v\n", c.name, c.err)
+		default:
+			fmt.Fprintf(w, "warn  %s: %v\n", c.name, c.err)
+		}
+		fmt.Fprintf(w, "      %s\n", c.hint)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d critical check(s) failed", failed)
+	}
+	return nil
+}
+
+// lookPathCheck checks that the named command is on PATH
+func lookPathCheck(name, hint string, critical bool) doctorCheck {
+	path, err := exec.LookPath(name)
+	return doctorCheck{name: name, err: err, detail: path, hint: hint, critical: critical}
+}
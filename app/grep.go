@@ -0,0 +1,91 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shadyabhi/mantee/man/parse"
+	"github.com/shadyabhi/mantee/man/search"
+	searchui "github.com/shadyabhi/mantee/search"
+	"github.com/shadyabhi/mantee/viewer"
+)
+
+// DefaultGrepPages is how many search results Grep searches by default
+const DefaultGrepPages = 10
+
+// maxGrepFetches bounds how many man processes Grep runs at once
+const maxGrepFetches = 4
+
+// Grep searches the bodies of the first pages results for keyword for term,
+// lists every line containing it, and opens the picked page at that line with
+// term searched
+func Grep(term, keyword string, pages int, opts Options) error {
+	opts.Search.Env = opts.Env
+	found, _, err := search.SearchManPages(keyword, opts.Search)
+	if err != nil {
+		return fmt.Errorf("searching man pages: %w", err)
+	}
+	if len(found) == 0 {
+		return fmt.Errorf("no man pages found for %q", keyword)
+	}
+	found = found[:min(pages, len(found))]
+
+	fetchOpts := opts.fetchOptions()
+	contents := make([]*parse.ManPageContent, len(found))
+	var wg sync.WaitGroup
+	limit := make(chan struct{}, maxGrepFetches)
+	for i, page := range found {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			limit <- struct{}{}
+			defer func() { <-limit }()
+			// A page that fails to fetch is skipped rather than failing the search
+			if content, err := parse.FetchManPage(page.Section, page.Name, fetchOpts); err == nil {
+				contents[i] = content
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Match like the viewer will once the page is opened with term searched
+	caseSensitive := opts.SmartCase && parse.CaseSensitive(term)
+	var hits []searchui.GrepHit
+	for i, content := range contents {
+		if content == nil {
+			continue
+		}
+		for _, line := range content.MatchingLines(term, caseSensitive) {
+			hits = append(hits, searchui.GrepHit{Page: found[i], Line: line, Text: strings.TrimSpace(content.Lines[line])})
+		}
+	}
+
+	finalModel, err := tea.NewProgram(searchui.NewGrep(term, keyword, len(found), hits)).Run()
+	if err != nil {
+		return fmt.Errorf("running grep results: %w", err)
+	}
+	hit := finalModel.(searchui.GrepModel).Selected()
+	if hit == nil {
+		return nil
+	}
+
+	store := openHistory()
+	if store != nil {
+		_ = store.Record(hit.Page)
+	}
+	if opts.OpenWithMan {
+		return openWithMan(hit.Page, opts.Env)
+	}
+
+	content := contents[slices.Index(found, hit.Page)]
+	vopts := opts.viewerOptions(store)
+	// Search term as grep matched it, spaces and quotes included
+	vopts.Search = term
+	vopts.SearchPhrase = true
+	vopts.StartLine = hit.Line
+	_, err = runViewer(viewer.New(hit.Page, content, vopts), opts.Inline)
+	return err
+}
@@ -0,0 +1,39 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shadyabhi/mantee/man/parse"
+	"github.com/shadyabhi/mantee/man/search"
+	"github.com/shadyabhi/mantee/viewer"
+)
+
+// ViewStdin shows formatted man output read from r in the viewer, e.g.
+// "mandoc -T utf8 tool.1 | mantee -". The page is named from its header
+// ("stdin" without one). Since r is stdin, keys are read from the terminal
+// instead.
+func ViewStdin(r io.Reader, opts Options) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("reading stdin: %w", err)
+	}
+	if strings.TrimSpace(string(data)) == "" {
+		return errors.New("nothing to show: stdin was empty")
+	}
+
+	content := parse.ParseManPage(string(data), opts.fetchOptions())
+	page := search.ManPage{Name: parse.ParseHeaderName(content.Lines), Section: content.Section}
+	if page.Name == "" {
+		page.Name = "stdin"
+	}
+
+	// The page isn't installed, so it isn't recorded as recent or pinnable
+	vopts := opts.viewerOptions(nil)
+	vopts.FromStdin = true
+	_, err = runViewer(viewer.New(page, content, vopts), opts.Inline, tea.WithInputTTY())
+	return err
+}
@@ -0,0 +1,109 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/shadyabhi/mantee/man"
+	"github.com/shadyabhi/mantee/man/parse"
+	"github.com/shadyabhi/mantee/man/search"
+)
+
+// pageFromArgs builds a ManPage from CLI args, either [name] or [section, name]
+func pageFromArgs(args []string) (search.ManPage, bool) {
+	switch len(args) {
+	case 1:
+		return search.ManPage{Name: args[0]}, true
+	case 2:
+		return search.ManPage{Section: args[0], Name: args[1]}, true
+	default:
+		return search.ManPage{}, false
+	}
+}
+
+// Which prints the path(s) of the source file(s) backing a man page.
+// args is either [name] or [section, name].
+func Which(w io.Writer, args []string, env man.Env) error {
+	page, ok := pageFromArgs(args)
+	if !ok {
+		return fmt.Errorf("--which expects [section] name")
+	}
+
+	paths, err := parse.LocateManPage(page.Section, page.Name, env)
+	if err != nil {
+		return fmt.Errorf("locating man page: %w", err)
+	}
+
+	for _, path := range paths {
+		fmt.Fprintln(w, path)
+	}
+	return nil
+}
+
+// ListOptions fetches a man page and prints its options as a two-column
+// cheatsheet (flags and a one-line explanation), the same table the viewer
+// copies. args is either [name] or [section, name].
+func ListOptions(w io.Writer, args []string, opts Options) error {
+	page, ok := pageFromArgs(args)
+	if !ok {
+		return fmt.Errorf("--list-options expects [section] name")
+	}
+
+	content, err := parse.FetchManPage(page.Section, page.Name, opts.fetchOptions())
+	if err != nil {
+		return fmt.Errorf("fetching man page: %w", err)
+	}
+	_, err = io.WriteString(w, parse.FormatOptionTable(content.Sections))
+	return err
+}
+
+// List searches for keyword and prints the results one per line, as
+// "name(section) - description", without starting the TUI. A note on stderr
+// says when --max-results cut the list short.
+func List(w io.Writer, keyword string, opts Options) error {
+	opts.Search.Env = opts.Env
+	pages, total, err := search.SearchManPages(keyword, opts.Search)
+	if err != nil {
+		return fmt.Errorf("searching man pages: %w", err)
+	}
+	if len(pages) == 0 {
+		return fmt.Errorf("no man pages found for: %s", keyword)
+	}
+	if _, err := io.WriteString(w, search.FormatResults(pages)); err != nil {
+		return err
+	}
+	if total > len(pages) {
+		fmt.Fprintf(os.Stderr, "showing %d of %d results; raise --max-results for more\n", len(pages), total)
+	}
+	return nil
+}
+
+// DebugParse fetches a man page and prints how it was parsed: man
+// sections, option sections and the option-like lines that were filtered
+// out. args is either [name] or [section, name].
+func DebugParse(w io.Writer, args []string, opts Options) error {
+	page, ok := pageFromArgs(args)
+	if !ok {
+		return fmt.Errorf("--debug-parse expects [section] name")
+	}
+
+	content, err := parse.FetchManPage(page.Section, page.Name, opts.fetchOptions())
+	if err != nil {
+		return fmt.Errorf("fetching man page: %w", err)
+	}
+	fmt.Fprintf(w, "Page: %s\n", page.Command())
+	_, err = io.WriteString(w, parse.FormatParseReport(content))
+	return err
+}
+
+// PrintCommand prints the man invocation that opens a page, e.g. "man 1 curl".
+// args is either [name] or [section, name].
+func PrintCommand(w io.Writer, args []string) error {
+	page, ok := pageFromArgs(args)
+	if !ok {
+		return fmt.Errorf("--print-command expects [section] name")
+	}
+	fmt.Fprintln(w, page.Command())
+	return nil
+}
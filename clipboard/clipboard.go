@@ -0,0 +1,52 @@
+package clipboard
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/aymanbagabas/go-osc52/v2"
+)
+
+// tool is an external clipboard command that reads the text from stdin
+type tool struct {
+	name string
+	args []string
+}
+
+// tools are tried in order; the first one found in PATH is used
+var tools = []tool{
+	{name: "pbcopy"},  // macOS
+	{name: "wl-copy"}, // Wayland
+	{name: "xclip", args: []string{"-selection", "clipboard"}}, // X11
+	{name: "xsel", args: []string{"--clipboard", "--input"}},   // X11
+	{name: "clip.exe"}, // WSL
+}
+
+// Write copies text to the system clipboard using the first available
+// clipboard tool. When none is installed it falls back to an OSC 52 escape
+// sequence, which most modern terminals (and tmux) honor, including over SSH.
+func Write(text string) error {
+	for _, t := range tools {
+		if _, err := exec.LookPath(t.name); err != nil {
+			continue
+		}
+		cmd := exec.Command(t.name, t.args...)
+		cmd.Stdin = strings.NewReader(text)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s: %w %s", t.name, err, strings.TrimSpace(stderr.String()))
+		}
+		return nil
+	}
+
+	seq := osc52.New(text)
+	if os.Getenv("TMUX") != "" {
+		seq = seq.Tmux()
+	}
+	_, err := seq.WriteTo(os.Stderr)
+	return err
+}
@@ -1,24 +1,268 @@
 package cmd
 
 import (
+	"flag"
 	"fmt"
+	"io"
+	"log"
 	"os"
+	"path/filepath"
+	"strings"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/shadyabhi/mantee/app"
+	"github.com/shadyabhi/mantee/config"
+	"github.com/shadyabhi/mantee/man"
+	"github.com/shadyabhi/mantee/man/parse"
+	"github.com/shadyabhi/mantee/man/search"
+	"github.com/shadyabhi/mantee/viewer"
 )
 
 // Execute is the main entry point for the CLI
 func Execute() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run parses CLI arguments and dispatches to the requested mode
+func run(args []string) error {
+	// Config values become flag defaults so flags override them
+	cfg, err := config.LoadDefault()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	flags := flag.NewFlagSet("mantee", flag.ExitOnError)
+	printCommand := flags.Bool("print-command", false, "print the man command that opens the page and exit")
+	keys := flags.Bool("keys", false, "print the viewer's keyboard shortcuts and exit")
+	list := flags.Bool("list", false, "print the search results for the keyword, one per line, and exit")
+	listOptions := flags.Bool("list-options", false, "print the page's options as a flags/summary table and exit")
+	doctor := flags.Bool("doctor", false, "check that man, man -k, col and a sample page work, print hints for what doesn't, and exit")
+	which := flags.Bool("which", false, "print the path of the man page source file(s) and exit")
+	regex := flags.Bool("regex", false, "interpret the keyword as a regular expression (apropos --regex)")
+	wildcard := flags.Bool("wildcard", false, "interpret the keyword as a shell wildcard (apropos --wildcard)")
+	names := flags.Bool("name", false, "keep only results whose page name starts with the keyword (or matches the --regex/--wildcard pattern), not just the description")
+	flags.BoolVar(names, "n", false, "shorthand for --name")
+	index := flags.Bool("index", false, "match page names from a scan of MANPATH instead of man -k (used automatically when man -k fails)")
+	sortMode := flags.String("sort", cfg.Sort, "order of search results: relevance (names starting with the keyword first) or section")
+	defaultMaxResults := search.DefaultMaxResults
+	if cfg.MaxResults != nil {
+		defaultMaxResults = *cfg.MaxResults
+	}
+	maxResults := flags.Int("max-results", defaultMaxResults, "list at most this many search results (0 lists all)")
+	keepDuplicates := flags.Bool("keep-duplicates", cfg.KeepDuplicates, "list every man -k entry, even repeats of a name(section) (by default one is kept, with the longest description)")
+	columns := flags.Bool("columns", cfg.Columns, "list search results in columns of name(section), like ls (toggle with c)")
+	openWithMan := flags.Bool("open-with-man", cfg.OpenWithMan, "open the selected page with man (and your pager) instead of the built-in viewer")
+	grep := flags.String("grep", "", "search the text of the pages found for keyword for this term and pick a matching line to open")
+	grepPages := flags.Int("grep-pages", app.DefaultGrepPages, "how many of the keyword's search results --grep searches")
+	inline := flags.Bool("inline", false, "run the viewer without the alternate screen, leaving the page in the terminal's scrollback on exit (disables mouse)")
+	expandTabs := flags.Bool("expand-tabs", false, "expand tabs to spaces like col -bx (keeps tables aligned)")
+	squeezeBlank := flags.Bool("squeeze-blank", false, "collapse runs of 3+ blank lines into one")
+	plain := flags.Bool("plain", false, "strip bold/underline with col -b instead of rendering them")
+	defaultSearch := flags.String("default-search", cfg.DefaultSearch, "search type started by / in the viewer: all, option, option-exact, description")
+	manPath := flags.String("manpath", cfg.ManPath, "MANPATH to search for pages (default: inherited)")
+	lang := flags.String("lang", cfg.Lang, "LANG for man, selecting translated pages (default: inherited)")
+	execPrefix := flags.String("exec", cfg.Exec, "run man under this command, e.g. 'ssh host' or 'docker exec box', to read pages there (default: local man)")
+	manArgs := flags.String("man-args", "", "extra arguments for man when fetching pages, e.g. '-a' or '-M /opt/man' (split on spaces)")
+	width := flags.Int("width", cfg.Width, fmt.Sprintf("format pages at a fixed width (MANWIDTH, %d-%d)", parse.MinWidth, parse.MaxWidth))
+	debugParse := flags.Bool("debug-parse", false, "print how the page is parsed and exit")
+	debugLog := flags.String("debug-log", "", "append key events, mode changes, man commands and timings to this file (MANTEE_DEBUG=1 logs to "+defaultDebugLog()+")")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: mantee [flags] [keyword]\n       mantee --which [section] name\n       mantee --print-command [section] name\n       mantee --list keyword\n       mantee --list-options [section] name\n       mantee --grep term keyword\n       mantee --keys\n       mantee --doctor\n       mantee - (formatted man page on stdin)\n\nFlags:\n")
+		printVisibleDefaults(flags)
+	}
+	flags.Parse(args)
+
+	closeLog, err := startDebugLog(*debugLog)
+	if err != nil {
+		return fmt.Errorf("opening debug log: %w", err)
+	}
+	defer closeLog()
+
+	if err := viewer.ValidateSearchType(*defaultSearch); err != nil {
+		return fmt.Errorf("invalid --default-search: %w", err)
+	}
+	if err := viewer.ValidateColor(cfg.CurrentMatchColor); err != nil {
+		return fmt.Errorf("invalid current_match_color in config: %w", err)
+	}
+	if err := viewer.ValidateColor(cfg.MatchColor); err != nil {
+		return fmt.Errorf("invalid match_color in config: %w", err)
+	}
+	if err := viewer.ValidateColor(cfg.FocusColor); err != nil {
+		return fmt.Errorf("invalid focus_color in config: %w", err)
+	}
+	if err := viewer.ValidateFocusBorder(cfg.FocusBorder); err != nil {
+		return fmt.Errorf("invalid focus_border in config: %w", err)
+	}
+	if err := viewer.ValidatePaneOrder(cfg.PaneOrder); err != nil {
+		return fmt.Errorf("invalid pane_order in config: %w", err)
+	}
+	if err := viewer.ValidateEnterAction(cfg.EnterAction); err != nil {
+		return fmt.Errorf("invalid enter_action in config: %w", err)
+	}
+	if *index && *execPrefix != "" {
+		return fmt.Errorf("--index scans local directories and can't be combined with --exec")
+	}
+	sortBy, err := search.ParseSortMode(*sortMode)
+	if err != nil {
+		return fmt.Errorf("invalid --sort: %w", err)
+	}
+
+	if *keys {
+		return viewer.WriteKeys(os.Stdout, viewer.Options{DefaultSearch: *defaultSearch, EnterAction: cfg.EnterAction})
+	}
+	env := man.Env{ManPath: *manPath, Lang: *lang, Exec: strings.Fields(*execPrefix)}
+	if *which {
+		return app.Which(os.Stdout, flags.Args(), env)
+	}
+	if *printCommand {
+		return app.PrintCommand(os.Stdout, flags.Args())
+	}
+
+	opts := app.Options{
+		Env:            env,
+		ConfirmQuit:    cfg.ConfirmQuit,
+		ScrollStep:     cfg.ScrollStep,
+		ScrollAccel:    cfg.ScrollAccel,
+		CenterCursor:   cfg.CenterCursor,
+		ScrollOff:      viewer.DefaultScrollOff,
+		ClearSearchTop: cfg.ClearSearchTop,
+		OpenWithMan:    *openWithMan,
+		StartupPage:    cfg.StartupPage,
+		Columns:        *columns,
+		Inline:         *inline,
+
+		CurrentMatchColor: cfg.CurrentMatchColor,
+		MatchColor:        cfg.MatchColor,
+		FocusColor:        cfg.FocusColor,
+		FocusBorder:       cfg.FocusBorder,
+		PaneOrder:         cfg.PaneOrder,
+		CenterContent:     cfg.CenterContent,
+		SectionRules:      cfg.SectionRules,
+		EnterAction:       cfg.EnterAction,
+		SmartCase:         cfg.SmartCase == nil || *cfg.SmartCase,
+	}
+	if cfg.ScrollOff != nil {
+		opts.ScrollOff = *cfg.ScrollOff
+	}
+	opts.ManArgs = strings.Fields(*manArgs)
+	if err := parse.ValidateManArgs(opts.ManArgs); err != nil {
+		return fmt.Errorf("invalid --man-args: %w", err)
+	}
+	if *width != 0 {
+		if err := parse.ValidateWidth(*width); err != nil {
+			return fmt.Errorf("invalid --width: %w", err)
+		}
+		opts.Width = *width
+	}
+
+	opts.DefaultSearch = *defaultSearch
+
+	if *expandTabs {
+		opts.Col = parse.ColExpandTabs
+	}
+	opts.Plain = *plain
+	opts.SqueezeBlank = *squeezeBlank
+
+	switch {
+	case *regex && *wildcard:
+		return fmt.Errorf("--regex and --wildcard are mutually exclusive")
+	case *regex:
+		opts.Search.Mode = search.MatchRegex
+	case *wildcard:
+		opts.Search.Mode = search.MatchWildcard
+	}
+	opts.Search.Index = *index
+	opts.Search.NamesOnly = *names
+	opts.Search.Sort = sortBy
+	if *maxResults < 0 {
+		return fmt.Errorf("invalid --max-results: must not be negative")
+	}
+	opts.Search.MaxResults = *maxResults
+	opts.Search.KeepDuplicates = *keepDuplicates
+
+	if *doctor {
+		return app.Doctor(os.Stdout, opts)
+	}
+	if *listOptions {
+		return app.ListOptions(os.Stdout, flags.Args(), opts)
+	}
+	if *debugParse {
+		return app.DebugParse(os.Stdout, flags.Args(), opts)
+	}
+
 	var keyword string
+	if flags.NArg() >= 1 {
+		keyword = flags.Arg(0)
+	}
 
-	// Parse CLI arguments
-	if len(os.Args) >= 2 {
-		keyword = os.Args[1]
+	if keyword == "-" {
+		if *grep != "" {
+			return fmt.Errorf("--grep can't search a page read from stdin")
+		}
+		return app.ViewStdin(os.Stdin, opts)
+	}
+
+	if *list {
+		if keyword == "" {
+			return fmt.Errorf("--list needs a keyword to search for")
+		}
+		return app.List(os.Stdout, keyword, opts)
+	}
+
+	if *grep != "" {
+		if keyword == "" {
+			return fmt.Errorf("--grep needs a keyword to find the pages to search")
+		}
+		if *grepPages < 1 {
+			return fmt.Errorf("invalid --grep-pages: must be at least 1")
+		}
+		return app.Grep(*grep, keyword, *grepPages, opts)
 	}
 
 	// Run the application
-	if err := app.Run(keyword); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+	return app.Run(keyword, opts)
+}
+
+// defaultDebugLog is where MANTEE_DEBUG=1 logs without --debug-log
+func defaultDebugLog() string {
+	return filepath.Join(os.TempDir(), "mantee-debug.log")
+}
+
+// startDebugLog sends the standard logger to path, or to defaultDebugLog when
+// path is empty and MANTEE_DEBUG=1. Otherwise logging is discarded, since
+// anything written to the terminal would corrupt the TUI. The returned
+// function closes the log.
+func startDebugLog(path string) (func(), error) {
+	if path == "" && os.Getenv("MANTEE_DEBUG") == "1" {
+		path = defaultDebugLog()
+	}
+	if path == "" {
+		log.SetOutput(io.Discard)
+		return func() {}, nil
+	}
+	f, err := tea.LogToFile(path, "mantee")
+	if err != nil {
+		return nil, err
 	}
+	log.SetFlags(log.LstdFlags | log.Lmicroseconds)
+	log.Printf("started: %q", os.Args)
+	return func() { f.Close() }, nil
+}
+
+// hiddenFlags are maintainer flags left out of the usage message
+var hiddenFlags = map[string]bool{"debug-parse": true}
+
+// printVisibleDefaults prints the defaults of every flag except hiddenFlags
+func printVisibleDefaults(flags *flag.FlagSet) {
+	visible := flag.NewFlagSet(flags.Name(), flag.ContinueOnError)
+	visible.SetOutput(flags.Output())
+	flags.VisitAll(func(f *flag.Flag) {
+		if !hiddenFlags[f.Name] {
+			visible.Var(f.Value, f.Name, f.Usage)
+		}
+	})
+	visible.PrintDefaults()
 }
@@ -0,0 +1,151 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Config holds persistent user settings. CLI flags take precedence over
+// these values.
+type Config struct {
+	// DefaultSearch is the search type "/" starts: "all", "option",
+	// "option-exact", or "description"
+	DefaultSearch string `json:"default_search,omitempty"`
+
+	// ManPath and Lang override MANPATH and LANG for man, e.g. to view
+	// project-local or translated pages
+	ManPath string `json:"manpath,omitempty"`
+	Lang    string `json:"lang,omitempty"`
+
+	// Exec is a command man runs under, e.g. "ssh host" or "docker exec
+	// box", to read pages on another machine or in a container
+	Exec string `json:"exec,omitempty"`
+
+	// ConfirmQuit asks before q or ctrl+s quits the viewer while a search
+	// is active or jumps have been made
+	ConfirmQuit bool `json:"confirm_quit,omitempty"`
+
+	// ScrollStep is how many lines j/k move in the content pane (default 1)
+	ScrollStep int `json:"scroll_step,omitempty"`
+
+	// ScrollAccel grows the j/k step while the key is held or pressed in
+	// quick succession, for getting through long pages
+	ScrollAccel bool `json:"scroll_accel,omitempty"`
+
+	// CenterCursor keeps the content cursor in the middle of the viewport,
+	// scrolling the page around it
+	CenterCursor bool `json:"center_cursor,omitempty"`
+
+	// ScrollOff is how many context lines stay visible above and below the
+	// content cursor. Nil means the viewer's default; 0 lets the cursor
+	// reach the viewport edge.
+	ScrollOff *int `json:"scroll_off,omitempty"`
+
+	// ClearSearchTop makes esc return the content and sidebar to the top
+	// when it clears a search, instead of keeping the reading position
+	ClearSearchTop bool `json:"clear_search_top,omitempty"`
+
+	// Sort orders search results: "relevance" (default) or "section"
+	Sort string `json:"sort,omitempty"`
+
+	// MaxResults caps the number of search results listed. Nil means the
+	// default cap; 0 lists every result.
+	MaxResults *int `json:"max_results,omitempty"`
+
+	// KeepDuplicates lists every man -k entry instead of one per
+	// name(section), e.g. to see the raw set when a page seems missing
+	KeepDuplicates bool `json:"keep_duplicates,omitempty"`
+
+	// SmartCase makes searches with an uppercase letter match case-sensitively,
+	// like vim's smartcase. Nil means the default (on).
+	SmartCase *bool `json:"smart_case,omitempty"`
+
+	// Columns lists search results as a grid of name(section) entries, like
+	// ls, instead of one per line with descriptions
+	Columns bool `json:"columns,omitempty"`
+
+	// OpenWithMan hands the selected page to man itself (and the user's
+	// pager) instead of the built-in viewer, using mantee only to find pages
+	OpenWithMan bool `json:"open_with_man,omitempty"`
+
+	// StartupPage is opened in the viewer when mantee starts without a
+	// keyword, as "[section] name" (e.g. "1 intro"), instead of the search
+	// prompt. ctrl+s in the viewer goes to the prompt.
+	StartupPage string `json:"startup_page,omitempty"`
+
+	// Width is the MANWIDTH pages are formatted at (0 for the default). It
+	// only affects formatting; the viewer still lays out panes for the real
+	// terminal size.
+	Width int `json:"width,omitempty"`
+
+	// CurrentMatchColor and MatchColor override the viewer's search
+	// highlights: the current occurrence and the other matching lines. Each
+	// is a 256-color number ("208") or "#rrggbb".
+	CurrentMatchColor string `json:"current_match_color,omitempty"`
+	MatchColor        string `json:"match_color,omitempty"`
+
+	// FocusColor and FocusBorder set how the viewer's focused pane stands
+	// out: its border color (a 256-color number or "#rrggbb") and style,
+	// "rounded", "normal", "thick", or "double". Other panes keep a gray
+	// rounded border.
+	FocusColor  string `json:"focus_color,omitempty"`
+	FocusBorder string `json:"focus_border,omitempty"`
+
+	// PaneOrder is the order tab cycles through the viewer's panes:
+	// "options", "content", and "sections". Panes left out are skipped.
+	PaneOrder []string `json:"pane_order,omitempty"`
+
+	// CenterContent centers pages much narrower than the viewer's content
+	// pane, e.g. an 80-column page in a wide terminal
+	CenterContent bool `json:"center_content,omitempty"`
+
+	// SectionRules draws a dim horizontal rule after each man section
+	// header in the viewer, so section boundaries stand out while scrolling
+	SectionRules bool `json:"section_rules,omitempty"`
+
+	// EnterAction is what enter does in the viewer's content pane: "follow"
+	// the name(section) reference on the line (default), "copy" the line,
+	// or "none"
+	EnterAction string `json:"enter_action,omitempty"`
+}
+
+// DefaultPath returns the config file location under the user config directory
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "mantee", "config.json"), nil
+}
+
+// Load reads the config file at path. A missing file yields the zero Config.
+func Load(path string) (Config, error) {
+	var cfg Config
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// LoadDefault reads the config file at DefaultPath
+func LoadDefault() (Config, error) {
+	path, err := DefaultPath()
+	if err != nil {
+		// No config directory (e.g. $HOME unset): behave as if no config exists
+		return Config{}, nil
+	}
+	return Load(path)
+}
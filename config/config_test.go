@@ -0,0 +1,49 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	data := `{"default_search": "option", "manpath": "/opt/man", "lang": "de_DE.UTF-8", "exec": "ssh build-host", "confirm_quit": true, "scroll_step": 3, "scroll_accel": true, "center_cursor": true, "scroll_off": 0, "clear_search_top": true, "sort": "section", "max_results": 0, "keep_duplicates": true, "columns": true, "smart_case": false, "open_with_man": true, "startup_page": "7 cheatsheet", "width": 100, "current_match_color": "33", "match_color": "#303030", "focus_color": "45", "focus_border": "thick", "pane_order": ["content", "options"], "center_content": true, "section_rules": true, "enter_action": "copy"}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	scrollOff := 0 // Explicit zero is kept, unlike an absent key
+	maxResults := 0
+	smartCase := false
+	want := Config{DefaultSearch: "option", ManPath: "/opt/man", Lang: "de_DE.UTF-8", Exec: "ssh build-host", ConfirmQuit: true, ScrollStep: 3, ScrollAccel: true, CenterCursor: true, ScrollOff: &scrollOff, ClearSearchTop: true, Sort: "section", MaxResults: &maxResults, KeepDuplicates: true, Columns: true, SmartCase: &smartCase, OpenWithMan: true, StartupPage: "7 cheatsheet", Width: 100, CurrentMatchColor: "33", MatchColor: "#303030", FocusColor: "45", FocusBorder: "thick", PaneOrder: []string{"content", "options"}, CenterContent: true, SectionRules: true, EnterAction: "copy"}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("Load() = %+v, want %+v", cfg, want)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !reflect.DeepEqual(cfg, Config{}) {
+		t.Errorf("Load() = %+v, want zero Config", cfg)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Load(path); err == nil {
+		t.Error("Load() expected error for invalid JSON")
+	}
+}
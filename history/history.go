@@ -0,0 +1,194 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/shadyabhi/mantee/man/search"
+)
+
+// maxEntries caps how many unpinned pages are remembered; pinned pages are
+// always kept
+const maxEntries = 50
+
+// Entry is a remembered man page
+type Entry struct {
+	Name        string    `json:"name"`
+	Section     string    `json:"section"`
+	Description string    `json:"description"`
+	OpenedAt    time.Time `json:"opened_at"`        // Zero for a page pinned but never opened
+	Pinned      bool      `json:"pinned,omitempty"` // Listed as a favorite
+}
+
+// Page returns the entry as a search.ManPage
+func (e Entry) Page() search.ManPage {
+	return search.ManPage{Name: e.Name, Section: e.Section, Description: e.Description}
+}
+
+// Store persists recently opened pages as JSON
+type Store struct {
+	path string
+}
+
+// DefaultPath returns the history file location under the user config directory
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "mantee", "history.json"), nil
+}
+
+// Open returns a Store backed by the file at path. The file is created lazily.
+func Open(path string) *Store {
+	return &Store{path: path}
+}
+
+// load reads all entries, newest first. A missing file is not an error.
+func (s *Store) load() ([]Entry, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", s.path, err)
+	}
+	return entries, nil
+}
+
+// save writes entries, creating the parent directory if needed
+func (s *Store) save(entries []Entry) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0o644)
+}
+
+// Recent returns up to n most recently opened pages, newest first
+func (s *Store) Recent(n int) ([]search.ManPage, error) {
+	entries, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	var pages []search.ManPage
+	for _, e := range entries {
+		if len(pages) == n {
+			break
+		}
+		if e.OpenedAt.IsZero() {
+			continue
+		}
+		pages = append(pages, e.Page())
+	}
+	return pages, nil
+}
+
+// Favorites returns the pinned pages sorted by name
+func (s *Store) Favorites() ([]search.ManPage, error) {
+	entries, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	var pages []search.ManPage
+	for _, e := range entries {
+		if e.Pinned {
+			pages = append(pages, e.Page())
+		}
+	}
+	sort.SliceStable(pages, func(i, j int) bool {
+		return pages[i].Name < pages[j].Name
+	})
+	return pages, nil
+}
+
+// IsPinned reports whether page is a favorite. Errors reading the store
+// count as not pinned.
+func (s *Store) IsPinned(page search.ManPage) bool {
+	entries, _ := s.load()
+	for _, e := range entries {
+		if e.Name == page.Name && e.Section == page.Section {
+			return e.Pinned
+		}
+	}
+	return false
+}
+
+// TogglePin pins page as a favorite, or unpins it if it already is, and
+// returns whether it is now pinned. A page not in the history is added
+// without counting as opened.
+func (s *Store) TogglePin(page search.ManPage) (bool, error) {
+	entries, err := s.load()
+	if err != nil {
+		return false, err
+	}
+
+	for i, e := range entries {
+		if e.Name == page.Name && e.Section == page.Section {
+			entries[i].Pinned = !e.Pinned
+			return entries[i].Pinned, s.save(trim(entries))
+		}
+	}
+	entries = append(entries, Entry{
+		Name:        page.Name,
+		Section:     page.Section,
+		Description: page.Description,
+		Pinned:      true,
+	})
+	return true, s.save(entries)
+}
+
+// trim drops the oldest unpinned entries beyond maxEntries
+func trim(entries []Entry) []Entry {
+	kept := entries[:0]
+	unpinned := 0
+	for _, e := range entries {
+		if !e.Pinned {
+			if unpinned == maxEntries {
+				continue
+			}
+			unpinned++
+		}
+		kept = append(kept, e)
+	}
+	return kept
+}
+
+// Record marks a page as opened now, moving it to the front of the history
+func (s *Store) Record(page search.ManPage) error {
+	entries, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	updated := []Entry{{
+		Name:        page.Name,
+		Section:     page.Section,
+		Description: page.Description,
+		OpenedAt:    time.Now(),
+	}}
+	for _, e := range entries {
+		if e.Name == page.Name && e.Section == page.Section {
+			updated[0].Pinned = e.Pinned
+			continue
+		}
+		updated = append(updated, e)
+	}
+	return s.save(trim(updated))
+}
@@ -0,0 +1,129 @@
+package history
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/shadyabhi/mantee/man/search"
+)
+
+func TestRecordAndRecent(t *testing.T) {
+	store := Open(filepath.Join(t.TempDir(), "mantee", "history.json"))
+
+	ls := search.ManPage{Name: "ls", Section: "1", Description: "list directory contents"}
+	grep := search.ManPage{Name: "grep", Section: "1", Description: "print lines that match patterns"}
+	printf := search.ManPage{Name: "printf", Section: "3", Description: "formatted output conversion"}
+
+	for _, p := range []search.ManPage{ls, grep, printf, ls} {
+		if err := store.Record(p); err != nil {
+			t.Fatalf("Record(%s) error = %v", p, err)
+		}
+	}
+
+	got, err := store.Recent(10)
+	if err != nil {
+		t.Fatalf("Recent() error = %v", err)
+	}
+	want := []search.ManPage{ls, printf, grep}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Recent() = %v, want %v", got, want)
+	}
+
+	got, _ = store.Recent(2)
+	if len(got) != 2 {
+		t.Errorf("Recent(2) returned %d pages", len(got))
+	}
+}
+
+func TestRecentMissingFile(t *testing.T) {
+	store := Open(filepath.Join(t.TempDir(), "missing.json"))
+
+	got, err := store.Recent(5)
+	if err != nil {
+		t.Fatalf("Recent() error = %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("Recent() = %v, want empty", got)
+	}
+}
+
+func TestRecentCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	if err := os.WriteFile(path, []byte("not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Open(path).Recent(5); err == nil {
+		t.Error("Recent() expected error for corrupt file")
+	}
+}
+
+func TestTogglePin(t *testing.T) {
+	store := Open(filepath.Join(t.TempDir(), "history.json"))
+
+	ls := search.ManPage{Name: "ls", Section: "1", Description: "list directory contents"}
+	tar := search.ManPage{Name: "tar", Section: "1", Description: "an archiving utility"}
+	if err := store.Record(ls); err != nil {
+		t.Fatal(err)
+	}
+
+	// Pinning works for opened and never-opened pages
+	for _, p := range []search.ManPage{tar, ls} {
+		pinned, err := store.TogglePin(p)
+		if err != nil || !pinned {
+			t.Fatalf("TogglePin(%s) = %v, %v, want true", p, pinned, err)
+		}
+	}
+	got, err := store.Favorites()
+	if err != nil {
+		t.Fatalf("Favorites() error = %v", err)
+	}
+	if want := []search.ManPage{ls, tar}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Favorites() = %v, want %v", got, want)
+	}
+
+	// A pinned page that was never opened is not recent
+	if got, _ := store.Recent(10); !reflect.DeepEqual(got, []search.ManPage{ls}) {
+		t.Errorf("Recent() = %v, want only ls", got)
+	}
+
+	// Opening a page keeps its pin
+	if err := store.Record(ls); err != nil {
+		t.Fatal(err)
+	}
+	if !store.IsPinned(ls) {
+		t.Error("IsPinned(ls) = false after Record, want true")
+	}
+
+	pinned, err := store.TogglePin(ls)
+	if err != nil || pinned {
+		t.Fatalf("TogglePin(ls) = %v, %v, want false", pinned, err)
+	}
+	if got, _ := store.Favorites(); !reflect.DeepEqual(got, []search.ManPage{tar}) {
+		t.Errorf("Favorites() after unpin = %v, want only tar", got)
+	}
+}
+
+func TestPinnedSurviveTrim(t *testing.T) {
+	store := Open(filepath.Join(t.TempDir(), "history.json"))
+
+	pinned := search.ManPage{Name: "pinned", Section: "1"}
+	if _, err := store.TogglePin(pinned); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i <= maxEntries; i++ {
+		if err := store.Record(search.ManPage{Name: fmt.Sprint("page", i), Section: "1"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got, _ := store.Favorites(); !reflect.DeepEqual(got, []search.ManPage{pinned}) {
+		t.Errorf("Favorites() = %v, want pinned page kept", got)
+	}
+	if got, _ := store.Recent(2 * maxEntries); len(got) != maxEntries {
+		t.Errorf("Recent() returned %d pages, want %d", len(got), maxEntries)
+	}
+}
@@ -0,0 +1,63 @@
+// Package mantest holds test helpers shared by the packages that run man:
+// a fake for their execCommand hook and testdata fixture loading.
+package mantest
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"testing"
+)
+
+// FakeExec replaces *execCommand for the duration of the test with one that
+// runs the test binary as a helper process printing stdout/stderr and
+// exiting with exitCode. The argv of every invocation is recorded in calls.
+// The calling package must define a TestHelperProcess that calls
+// HelperProcess.
+func FakeExec(t *testing.T, execCommand *func(string, ...string) *exec.Cmd, stdout, stderr string, exitCode int) *[][]string {
+	t.Helper()
+	var calls [][]string
+	orig := *execCommand
+	*execCommand = func(name string, args ...string) *exec.Cmd {
+		calls = append(calls, append([]string{name}, args...))
+		cmd := exec.Command(os.Args[0], "-test.run=TestHelperProcess")
+		cmd.Env = append(os.Environ(),
+			"GO_WANT_HELPER_PROCESS=1",
+			"HELPER_STDOUT="+stdout,
+			"HELPER_STDERR="+stderr,
+			"HELPER_EXIT="+strconv.Itoa(exitCode),
+		)
+		return cmd
+	}
+	t.Cleanup(func() { *execCommand = orig })
+	return &calls
+}
+
+// HelperProcess is the fake external command spawned by FakeExec; call it
+// from the package's TestHelperProcess. Outside a helper process it returns
+// at once. With HELPER_EXPAND=1, $VARS in stdout are expanded from the
+// helper's environment so tests can observe what it inherited.
+func HelperProcess() {
+	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	stdout := os.Getenv("HELPER_STDOUT")
+	if os.Getenv("HELPER_EXPAND") == "1" {
+		stdout = os.ExpandEnv(stdout)
+	}
+	fmt.Fprint(os.Stdout, stdout)
+	fmt.Fprint(os.Stderr, os.Getenv("HELPER_STDERR"))
+	code, _ := strconv.Atoi(os.Getenv("HELPER_EXIT"))
+	os.Exit(code)
+}
+
+// ReadFixture returns the content of a file in the package's testdata
+func ReadFixture(t *testing.T, name string) string {
+	t.Helper()
+	b, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+	return string(b)
+}
@@ -0,0 +1,93 @@
+// Package man holds settings shared by every man invocation
+package man
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Env is the environment overrides applied to man child processes, e.g. to
+// view translated or project-local pages. Empty fields inherit the current
+// environment.
+type Env struct {
+	ManPath string // MANPATH: directories searched for pages
+	Lang    string // LANG: locale used to pick translated pages
+
+	// Exec is a command prefix man runs under, e.g. ["ssh", "host"] or
+	// ["docker", "exec", "box"], to read pages on another machine or in a
+	// container. Nil runs man locally.
+	Exec []string
+}
+
+// vars returns the overrides as KEY=value pairs
+func (e Env) vars() []string {
+	var vars []string
+	if e.ManPath != "" {
+		vars = append(vars, "MANPATH="+e.ManPath)
+	}
+	if e.Lang != "" {
+		vars = append(vars, "LANG="+e.Lang)
+	}
+	return vars
+}
+
+// Command returns the argv that runs man with args. Locally that is just
+// "man"; under Exec the overrides and extraVars (e.g. MANWIDTH=100) are
+// passed through env(1), since the local environment doesn't reach a remote
+// host or container. ssh joins its arguments into a command line for the
+// remote shell, so they are shell-quoted for it; other prefixes (docker exec,
+// kubectl exec) pass arguments through as they are.
+func (e Env) Command(extraVars []string, args ...string) []string {
+	if len(e.Exec) == 0 {
+		return append([]string{"man"}, args...)
+	}
+	remote := append([]string{"env"}, e.vars()...)
+	remote = append(remote, extraVars...)
+	remote = append(remote, "man")
+	remote = append(remote, args...)
+	if filepath.Base(e.Exec[0]) == "ssh" {
+		for i, arg := range remote {
+			remote[i] = ShellQuote(arg)
+		}
+	}
+	return append(slices.Clone(e.Exec), remote...)
+}
+
+// shellSafeRe matches words a POSIX shell reads literally
+var shellSafeRe = regexp.MustCompile(`^[A-Za-z0-9_./:=+,@%-]+$`)
+
+// ShellQuote returns s quoted for a POSIX shell, or as is when the shell
+// would read it literally anyway
+func ShellQuote(s string) string {
+	if shellSafeRe.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// ShellJoin quotes each argument with ShellQuote and joins them with spaces
+func ShellJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = ShellQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// Apply adds the overrides to cmd's environment, starting from the current
+// process environment when cmd has none of its own. Later entries win, so
+// the overrides replace any inherited values.
+func (e Env) Apply(cmd *exec.Cmd) {
+	vars := e.vars()
+	if len(vars) == 0 {
+		return
+	}
+	if cmd.Env == nil {
+		cmd.Env = os.Environ()
+	}
+	cmd.Env = append(cmd.Env, vars...)
+}
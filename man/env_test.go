@@ -0,0 +1,82 @@
+package man
+
+import (
+	"os/exec"
+	"slices"
+	"testing"
+)
+
+func TestEnvApply(t *testing.T) {
+	t.Setenv("MANPATH", "/usr/share/man")
+
+	cmd := exec.Command("man")
+	Env{ManPath: "/opt/project/man", Lang: "de_DE.UTF-8"}.Apply(cmd)
+
+	if !slices.Contains(cmd.Env, "MANPATH=/usr/share/man") {
+		t.Error("inherited environment not kept")
+	}
+	// exec uses the last value for duplicate keys
+	if n := len(cmd.Env); n < 2 || cmd.Env[n-2] != "MANPATH=/opt/project/man" || cmd.Env[n-1] != "LANG=de_DE.UTF-8" {
+		t.Errorf("overrides not appended: %v", cmd.Env)
+	}
+}
+
+func TestEnvApplyEmpty(t *testing.T) {
+	cmd := exec.Command("man")
+	Env{}.Apply(cmd)
+
+	if cmd.Env != nil {
+		t.Errorf("empty Env should inherit the environment, got %v", cmd.Env)
+	}
+}
+
+func TestEnvCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		env  Env
+		want []string
+	}{
+		{"local", Env{Lang: "C"}, []string{"man", "-w", "ls"}},
+		{"docker", Env{Lang: "C", Exec: []string{"docker", "exec", "box"}},
+			[]string{"docker", "exec", "box", "env", "LANG=C", "MANWIDTH=100", "man", "-w", "ls"}},
+		{"ssh", Env{Exec: []string{"/usr/bin/ssh", "host"}},
+			[]string{"/usr/bin/ssh", "host", "env", "MANWIDTH=100", "man", "-w", "ls"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.env.Command([]string{"MANWIDTH=100"}, "-w", "ls"); !slices.Equal(got, tt.want) {
+				t.Errorf("Command() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnvCommandQuotesForSSH(t *testing.T) {
+	got := Env{Exec: []string{"ssh", "host"}}.Command(nil, "ls; rm -rf ~")
+	if last := got[len(got)-1]; last != "'ls; rm -rf ~'" {
+		t.Errorf("argument not quoted for the remote shell: %q", last)
+	}
+
+	got = Env{Exec: []string{"docker", "exec", "box"}}.Command(nil, "ls; rm -rf ~")
+	if last := got[len(got)-1]; last != "ls; rm -rf ~" {
+		t.Errorf("argument quoted without a remote shell: %q", last)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	tests := map[string]string{
+		"ls":           "ls",
+		"MANWIDTH=80":  "MANWIDTH=80",
+		"":             "''",
+		"a b":          "'a b'",
+		"it's":         `'it'\''s'`,
+		"$(reboot)":    "'$(reboot)'",
+		"resolv.conf":  "resolv.conf",
+		"perl::Module": "perl::Module",
+	}
+	for in, want := range tests {
+		if got := ShellQuote(in); got != want {
+			t.Errorf("ShellQuote(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
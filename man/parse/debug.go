@@ -0,0 +1,83 @@
+package parse
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// optionLikeRe matches any line starting with something shaped like a flag,
+// whatever its indentation
+var optionLikeRe = regexp.MustCompile(`^\s*(-\S|--[a-zA-Z])`)
+
+// RejectedOption is a line that looks like an option but was not parsed as
+// an option definition
+type RejectedOption struct {
+	Line   int    // Line number in the man page content
+	Text   string // The line, trimmed
+	Reason string // Why it was filtered out, e.g. "too long"
+}
+
+// RejectedOptions returns the option-like lines that parseOptionSections
+// skipped, with the reason for each
+func RejectedOptions(lines []string) []RejectedOption {
+	var rejected []RejectedOption
+	for i, line := range lines {
+		if !optionLikeRe.MatchString(line) {
+			continue
+		}
+		if reason := optionRejection(line); reason != "" {
+			rejected = append(rejected, RejectedOption{
+				Line:   i,
+				Text:   strings.TrimSpace(line),
+				Reason: reason,
+			})
+		}
+	}
+	return rejected
+}
+
+// FormatParseReport describes how content was parsed: its man sections and
+// option sections with their line ranges, and the option-like lines that
+// were filtered out. Line numbers are 0-based indexes into content.Lines.
+// It is meant for diagnosing an empty or wrong sidebar.
+func FormatParseReport(content *ManPageContent) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Lines: %d  Header section: %q  Parse time: %s\n", len(content.Lines), content.Section, content.ParseDuration)
+
+	fmt.Fprintf(&b, "\nMan sections (%d):\n", len(content.ManSections))
+	for _, s := range content.ManSections {
+		fmt.Fprintf(&b, "  %5d-%-5d  %s\n", s.StartLine, s.EndLine, s.Name)
+		for _, sub := range s.Subsections {
+			fmt.Fprintf(&b, "  %5d-%-5d    %s\n", sub.StartLine, sub.EndLine, sub.Name)
+		}
+	}
+
+	fmt.Fprintf(&b, "\nOption sections (%d):\n", len(content.Sections))
+	for _, s := range content.Sections {
+		fmt.Fprintf(&b, "  %5d-%-5d  %s\n", s.StartLine, s.EndLine, s.Option)
+		fmt.Fprintf(&b, "               flags: %s\n", ExtractOptionFlags(s.Option))
+		if first := firstExplanationLine(content.Lines, s); first != "" {
+			fmt.Fprintf(&b, "               explanation: %s\n", first)
+		}
+	}
+
+	rejected := RejectedOptions(content.Lines)
+	fmt.Fprintf(&b, "\nRejected option-like lines (%d):\n", len(rejected))
+	for _, r := range rejected {
+		fmt.Fprintf(&b, "  %5d  %-17s  %s\n", r.Line, r.Reason, r.Text)
+	}
+	return b.String()
+}
+
+// firstExplanationLine returns the first non-blank line of a section's
+// explanation, or the text after the flags for options written on one line
+// with their description
+func firstExplanationLine(lines []string, s Section) string {
+	for i := s.StartLine + 1; i <= s.EndLine && i < len(lines); i++ {
+		if line := strings.TrimSpace(lines[i]); line != "" {
+			return line
+		}
+	}
+	return strings.TrimSpace(strings.TrimPrefix(s.Option, ExtractOptionFlags(s.Option)))
+}
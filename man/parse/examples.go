@@ -0,0 +1,79 @@
+package parse
+
+import "strings"
+
+// CodeBlock is a run of example commands or code in a man page
+type CodeBlock struct {
+	StartLine int // First line of the block
+	EndLine   int // Last line of the block (inclusive)
+}
+
+// Code returns the block's lines with the indentation they share removed,
+// ready to paste into a shell
+func (b CodeBlock) Code(lines []string) string {
+	block := lines[b.StartLine : b.EndLine+1]
+	common := -1
+	for _, line := range block {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if n := len(line) - len(strings.TrimLeft(line, " ")); common == -1 || n < common {
+			common = n
+		}
+	}
+
+	var out []string
+	for _, line := range block {
+		if len(line) >= common {
+			line = line[common:]
+		}
+		out = append(out, strings.TrimRight(line, " "))
+	}
+	return strings.Join(out, "\n")
+}
+
+// parseExampleBlocks finds the code in EXAMPLES sections: runs of lines
+// indented deeper than the section's prose, or shell prompts ("$ cmd").
+// Blank lines between code lines stay within a block.
+func parseExampleBlocks(lines []string, manSections []ManSection) []CodeBlock {
+	var blocks []CodeBlock
+	for _, ms := range manSections {
+		if !strings.HasPrefix(ms.Name, "EXAMPLE") {
+			continue
+		}
+
+		// The prose is at the shallowest indentation in the section
+		base := -1
+		for i := ms.StartLine + 1; i <= ms.EndLine && i < len(lines); i++ {
+			if strings.TrimSpace(lines[i]) == "" {
+				continue
+			}
+			if n := indentWidth(lines[i]); base == -1 || n < base {
+				base = n
+			}
+		}
+
+		start, last := -1, -1
+		for i := ms.StartLine + 1; i <= ms.EndLine && i < len(lines); i++ {
+			trimmed := strings.TrimSpace(lines[i])
+			if trimmed == "" {
+				continue
+			}
+			if indentWidth(lines[i]) > base || strings.HasPrefix(trimmed, "$ ") {
+				if start == -1 {
+					start = i
+				}
+				last = i
+				continue
+			}
+			if start != -1 {
+				blocks = append(blocks, CodeBlock{StartLine: start, EndLine: last})
+				start = -1
+			}
+		}
+		if start != -1 {
+			blocks = append(blocks, CodeBlock{StartLine: start, EndLine: last})
+		}
+	}
+	return blocks
+}
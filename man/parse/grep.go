@@ -0,0 +1,24 @@
+package parse
+
+import "strings"
+
+// MatchingLines returns the indexes of the lines containing query, for
+// searching a page without viewing it. Matching ignores case unless
+// caseSensitive is set (see CaseSensitive for smartcase).
+func (c *ManPageContent) MatchingLines(query string, caseSensitive bool) []int {
+	if query == "" {
+		return nil
+	}
+	fold := strings.ToLower
+	if caseSensitive {
+		fold = func(s string) string { return s }
+	}
+	query = fold(query)
+	var lines []int
+	for i, line := range c.Lines {
+		if strings.Contains(fold(line), query) {
+			lines = append(lines, i)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,151 @@
+package parse
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// CaseSensitive reports whether query should match case-sensitively under
+// smartcase (as in vim): only when it contains an uppercase letter. As in
+// vim, a letter after a backslash doesn't count, so regex escapes like \S
+// and \W leave the search case-insensitive.
+func CaseSensitive(query string) bool {
+	escaped := false
+	for _, r := range query {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case unicode.IsUpper(r):
+			return true
+		}
+	}
+	return false
+}
+
+// SectionIndex caches lowercased option text for a page's Sections so that
+// case-insensitive searches don't re-lowercase every section on each
+// keystroke. Results match the corresponding Section.Matches* methods.
+type SectionIndex struct {
+	entries []indexEntry
+}
+
+// indexEntry holds the precomputed match text of one Section
+type indexEntry struct {
+	option      string   // Lowercased Option
+	flags       string   // Lowercased option flags (see ExtractOptionFlags)
+	explanation string   // Lowercased Explanation
+	exactFlags  []string // Names of the individual flags (see flagNames), original case
+	grouped     []string // Short flags of grouped tokens like "-abc" (see groupedFlags)
+
+	// Option, flags and Explanation in their original case, for
+	// case-sensitive matching
+	casedOption, casedFlags, casedExplanation string
+}
+
+// NewSectionIndex builds an index over sections. Match results are indices
+// into this slice.
+func NewSectionIndex(sections []Section) *SectionIndex {
+	entries := make([]indexEntry, len(sections))
+	for i, s := range sections {
+		var exact, grouped []string
+		for _, token := range flagTokens(s.Option) {
+			exact = append(exact, flagNames(token)...)
+			grouped = append(grouped, groupedFlags(token)...)
+		}
+		flags := ExtractOptionFlags(s.Option)
+		entries[i] = indexEntry{
+			option:      strings.ToLower(s.Option),
+			flags:       strings.ToLower(flags),
+			explanation: strings.ToLower(s.Explanation),
+			exactFlags:  exact,
+			grouped:     grouped,
+
+			casedOption:      s.Option,
+			casedFlags:       flags,
+			casedExplanation: s.Explanation,
+		}
+	}
+	return &SectionIndex{entries: entries}
+}
+
+// filter returns the indices of entries for which match reports true
+func (x *SectionIndex) filter(match func(e *indexEntry) bool) []int {
+	var indices []int
+	for i := range x.entries {
+		if match(&x.entries[i]) {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// MatchQuery returns the sections whose option or explanation contains query
+// (case-insensitive unless caseSensitive), like Section.MatchesQuery
+func (x *SectionIndex) MatchQuery(query string, caseSensitive bool) []int {
+	if caseSensitive {
+		return x.filter(func(e *indexEntry) bool {
+			return strings.Contains(e.casedOption, query) || strings.Contains(e.casedExplanation, query)
+		})
+	}
+	query = strings.ToLower(query)
+	return x.filter(func(e *indexEntry) bool {
+		return strings.Contains(e.option, query) || strings.Contains(e.explanation, query)
+	})
+}
+
+// MatchOption returns the sections whose option flags contain query
+// (case-insensitive unless caseSensitive), like Section.MatchesOption
+func (x *SectionIndex) MatchOption(query string, caseSensitive bool) []int {
+	if caseSensitive {
+		return x.filter(func(e *indexEntry) bool {
+			return strings.Contains(e.casedFlags, query)
+		})
+	}
+	query = strings.ToLower(query)
+	return x.filter(func(e *indexEntry) bool {
+		return strings.Contains(e.flags, query)
+	})
+}
+
+// MatchOptionExact returns the sections with a flag exactly matching query
+// (case-sensitive, leading dashes ignored), like Section.MatchesOptionExact
+func (x *SectionIndex) MatchOptionExact(query string) []int {
+	if query == "" {
+		return x.filter(func(*indexEntry) bool { return true })
+	}
+	query = strings.TrimLeft(query, "-")
+	return x.filter(func(e *indexEntry) bool {
+		for _, flag := range e.exactFlags {
+			if flag == query {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// MatchOptionGrouped returns the sections with a grouped short flag
+// containing query, like Section.MatchesOptionGrouped
+func (x *SectionIndex) MatchOptionGrouped(query string) []int {
+	query = strings.TrimLeft(query, "-")
+	return x.filter(func(e *indexEntry) bool {
+		return slices.Contains(e.grouped, query)
+	})
+}
+
+// MatchDescription returns the sections whose explanation contains query
+// (case-insensitive unless caseSensitive), like Section.MatchesDescription
+func (x *SectionIndex) MatchDescription(query string, caseSensitive bool) []int {
+	if caseSensitive {
+		return x.filter(func(e *indexEntry) bool {
+			return strings.Contains(e.casedExplanation, query)
+		})
+	}
+	query = strings.ToLower(query)
+	return x.filter(func(e *indexEntry) bool {
+		return strings.Contains(e.explanation, query)
+	})
+}
@@ -0,0 +1,213 @@
+package parse
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Style is text emphasis recovered from man's formatted output
+type Style uint8
+
+const (
+	StyleBold      Style = 1 << iota // c\bc overstrike or SGR 1
+	StyleUnderline                   // _\bc overstrike or SGR 4
+)
+
+// StyledRun is an emphasized span of a line, as byte offsets into the line
+type StyledRun struct {
+	Start int
+	End   int
+	Style Style
+}
+
+// tabWidth is the tab stop interval used when expanding tabs
+const tabWidth = 8
+
+// cell is a decoded character and the emphasis applied to it
+type cell struct {
+	r     rune
+	style Style
+}
+
+// decodeFormatting converts man's formatted output into plain text plus
+// per-line emphasis runs. It understands the overstrike sequences emitted by
+// nroff/grotty (c\bc = bold, _\bc = underline) as well as SGR escapes
+// (ESC[1m, ESC[4m, ...) used by newer groff. Other escape sequences are
+// dropped. With expandTabs, tabs are replaced with spaces up to the next stop.
+func decodeFormatting(raw string, expandTabs bool) (string, [][]StyledRun) {
+	rawLines := strings.Split(raw, "\n")
+	lines := make([]string, len(rawLines))
+	styles := make([][]StyledRun, len(rawLines))
+	for i, rawLine := range rawLines {
+		lines[i], styles[i] = decodeLine(rawLine, expandTabs)
+	}
+	return strings.Join(lines, "\n"), styles
+}
+
+// decodeLine decodes a single line of formatted output (see decodeFormatting)
+func decodeLine(raw string, expandTabs bool) (string, []StyledRun) {
+	var cells []cell
+	var sgr Style // Emphasis currently set by SGR escapes
+	overstrike := false
+
+	runes := []rune(raw)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\b':
+			overstrike = len(cells) > 0
+			continue
+
+		case r == '\x1b':
+			i = skipEscape(runes, i, &sgr)
+			continue
+
+		case r == '\r':
+			continue
+
+		case r < ' ' && r != '\t' || r == '\x7f':
+			// Other control characters would throw off width calculations
+			continue
+
+		case r == '\t' && expandTabs:
+			for {
+				cells = append(cells, cell{r: ' ', style: sgr})
+				if len(cells)%tabWidth == 0 {
+					break
+				}
+			}
+			continue
+		}
+
+		if overstrike {
+			overstrike = false
+			prev := &cells[len(cells)-1]
+			switch {
+			case prev.r == r:
+				prev.style |= StyleBold
+			case prev.r == '_':
+				prev.r = r
+				prev.style |= StyleUnderline
+			case r == '_':
+				prev.style |= StyleUnderline
+			default:
+				// Overstrike of different characters (e.g. "+\bo" bullets):
+				// keep the last one, as col -b does
+				prev.r = r
+			}
+			continue
+		}
+
+		cells = append(cells, cell{r: r, style: sgr})
+	}
+
+	// Build the plain line and merge cells with equal emphasis into runs
+	var b strings.Builder
+	var runs []StyledRun
+	for _, c := range cells {
+		start := b.Len()
+		b.WriteRune(c.r)
+		if c.style == 0 {
+			continue
+		}
+		if n := len(runs); n > 0 && runs[n-1].End == start && runs[n-1].Style == c.style {
+			runs[n-1].End = b.Len()
+			continue
+		}
+		runs = append(runs, StyledRun{Start: start, End: b.Len(), Style: c.style})
+	}
+	return b.String(), runs
+}
+
+// stripControlSequences removes escape sequences and control characters
+// (other than tabs and newlines) that col may leave in plain output, so every
+// byte of a line is printable text
+func stripControlSequences(raw string) string {
+	if !strings.ContainsFunc(raw, isStrayControl) {
+		return raw
+	}
+
+	var b strings.Builder
+	var sgr Style // Unused; skipEscape requires it
+	runes := []rune(raw)
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; {
+		case r == '\x1b':
+			i = skipEscape(runes, i, &sgr)
+		case isStrayControl(r):
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// isStrayControl reports whether r is a control character other than the
+// tab and newline that plain man output legitimately contains
+func isStrayControl(r rune) bool {
+	return r < ' ' && r != '\t' && r != '\n' || r == '\x7f'
+}
+
+// skipEscape consumes the escape sequence starting at runes[i] and returns the
+// index of its last rune. SGR sequences update sgr; anything else is ignored.
+func skipEscape(runes []rune, i int, sgr *Style) int {
+	if i+1 < len(runes) && strings.ContainsRune("]P_^X", runes[i+1]) {
+		return skipEscapeString(runes, i)
+	}
+	if i+1 >= len(runes) || runes[i+1] != '[' {
+		// Two-character escape (e.g. ESC 7); drop both
+		return min(i+1, len(runes)-1)
+	}
+
+	// CSI: parameters up to a final byte in the range @ to ~
+	j := i + 2
+	for j < len(runes) && (runes[j] < '@' || runes[j] > '~') {
+		j++
+	}
+	if j >= len(runes) {
+		return len(runes) - 1
+	}
+	if runes[j] == 'm' {
+		applySGR(string(runes[i+2:j]), sgr)
+	}
+	return j
+}
+
+// skipEscapeString consumes an OSC, DCS, SOS, PM or APC sequence starting
+// at runes[i], e.g. groff's OSC 8 hyperlinks or ESC ]0;title BEL, and returns
+// the index of its last rune. The payload runs to BEL or ST (ESC \), or to
+// the end of the text when unterminated.
+func skipEscapeString(runes []rune, i int) int {
+	for j := i + 2; j < len(runes); j++ {
+		switch {
+		case runes[j] == '\a':
+			return j
+		case runes[j] == '\x1b' && j+1 < len(runes) && runes[j+1] == '\\':
+			return j + 1
+		}
+	}
+	return len(runes) - 1
+}
+
+// applySGR updates sgr from the semicolon-separated parameters of an SGR
+// escape. Only bold and underline are tracked; colors are ignored.
+func applySGR(params string, sgr *Style) {
+	for _, p := range strings.Split(params, ";") {
+		code, err := strconv.Atoi(p)
+		if err != nil && p != "" {
+			continue
+		}
+		switch code {
+		case 0: // Also the empty parameter
+			*sgr = 0
+		case 1:
+			*sgr |= StyleBold
+		case 4:
+			*sgr |= StyleUnderline
+		case 22:
+			*sgr &^= StyleBold
+		case 24:
+			*sgr &^= StyleUnderline
+		}
+	}
+}
@@ -2,17 +2,35 @@ package parse
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
+	"log"
 	"os/exec"
 	"regexp"
+	"slices"
 	"strings"
+	"time"
+	"unicode"
+
+	"github.com/shadyabhi/mantee/man"
 )
 
 const (
 	// Maximum length for a line containing just option flags
 	// Description text lines are typically much longer
 	maxOptionLineLength = 60
+
+	// DefaultWidth is the MANWIDTH used when no width is requested
+	DefaultWidth = 80
+	// MinWidth and MaxWidth bound user-requested widths
+	MinWidth = 20
+	MaxWidth = 1000
 )
 
+// execCommand is the constructor used for external commands; tests replace it
+// to inject canned output
+var execCommand = exec.Command
+
 // Section represents a CLI option section from a man page
 type Section struct {
 	Option      string // The CLI option(s), e.g., "-r, --recursive"
@@ -22,43 +40,328 @@ type Section struct {
 }
 
 // ManSection represents a major section in a man page (NAME, SYNOPSIS, DESCRIPTION, etc.)
+// or a subsection within one
 type ManSection struct {
-	Name      string // The section name, e.g., "NAME", "SYNOPSIS", "DESCRIPTION"
-	StartLine int    // Line number where this section starts
+	Name        string       // The section name, e.g., "NAME", "SYNOPSIS", "DESCRIPTION"
+	Level       int          // 0 for a section, 1 for a subsection
+	StartLine   int          // Line number where this section starts
+	EndLine     int          // Line number where this section ends (inclusive)
+	Subsections []ManSection // Subsections within this section, in page order
 }
 
 // ManPageContent represents the full content of a man page
 type ManPageContent struct {
-	RawContent  string       // The full man page text
-	Lines       []string     // Lines of the man page
-	Sections    []Section    // Parsed option sections
-	ManSections []ManSection // Major man page sections (NAME, SYNOPSIS, etc.)
+	Section     string        // Section from the page's own header, e.g. "3" for PRINTF(3) ("" if not found)
+	RawContent  string        // The full man page text (without formatting)
+	Lines       []string      // Lines of the man page
+	Styles      [][]StyledRun // Bold/underline runs per line (nil when fetched with Plain)
+	Sections    []Section     // Parsed option sections
+	ManSections []ManSection  // Major man page sections (NAME, SYNOPSIS, etc.)
+	Examples    []CodeBlock   // Code blocks in EXAMPLES sections
+
+	// ParseDuration is the time spent decoding and parsing man's output (not
+	// running man itself), for diagnosing slow pages
+	ParseDuration time.Duration
+}
+
+// ColMode selects how tabs in man's output are handled. The names mirror the
+// col flags used on the Plain path; the default path applies the same
+// choice while decoding formatting.
+type ColMode int
+
+const (
+	ColStrip      ColMode = iota // col -b: strip overstrike formatting
+	ColExpandTabs                // col -bx: also expand tabs to spaces, keeping table columns aligned
+)
+
+// args returns the col flags for the mode
+func (c ColMode) args() string {
+	if c == ColExpandTabs {
+		return "-bx"
+	}
+	return "-b"
+}
+
+// String returns a short description of the mode
+func (c ColMode) String() string {
+	if c == ColExpandTabs {
+		return "col -bx"
+	}
+	return "col -b"
+}
+
+// FetchOptions controls how a man page is fetched and formatted
+type FetchOptions struct {
+	Width int     // MANWIDTH to format the page at (DefaultWidth when zero)
+	Col   ColMode // How tabs are handled
+	Plain bool    // Strip formatting with col instead of decoding bold/underline
+	Env   man.Env // Environment overrides (MANPATH, LANG) for man
+
+	// ManArgs are extra arguments for man, e.g. ["-a"] or ["-M", "/opt/man"],
+	// passed before the page (see ValidateManArgs)
+	ManArgs []string
+
+	// SqueezeBlank collapses runs of 3+ blank lines into one (trailing blank
+	// lines are always trimmed)
+	SqueezeBlank bool
+}
+
+// ValidateWidth checks that a requested content width is within a sane range
+func ValidateWidth(width int) error {
+	if width < MinWidth || width > MaxWidth {
+		return fmt.Errorf("width %d out of range (%d-%d)", width, MinWidth, MaxWidth)
+	}
+	return nil
+}
+
+// unsafeManArgs are man flags that make it print something other than the
+// page (a path, search results, troff or HTML) or wait for input, which the
+// parser can't use
+var unsafeManArgs = []string{
+	"-w", "--where", "--path", "--location", "-W", "--where-cat", "--location-cat",
+	"-k", "--apropos", "-K", "--global-apropos", "-f", "--whatis",
+	"-t", "--troff", "-T", "--troff-device", "-H", "--html", "-X", "--gxditview", "-Z", "--ditroff",
+	"-h", "--help", "-?", "-V", "--version", "--usage",
 }
 
-// FetchManPage retrieves the content of a man page
-func FetchManPage(section, name string) (*ManPageContent, error) {
-	// Use MANWIDTH to control line width, and col -b to strip formatting
-	cmd := exec.Command("sh", "-c", "MANWIDTH=80 man "+section+" "+name+" | col -b")
+// manValueFlags are the letters of man's short flags that take a value,
+// which may be attached (e.g. "-M/opt/man" or "-Pcat")
+const manValueFlags = "CeELmMpPrRsS"
+
+// shortManFlags splits a cluster of short flags like "-aw" into "-a" and
+// "-w". It stops at a flag taking a value, so the value attached to it
+// ("-Pcat") isn't read as more flags. Other arguments are returned as is.
+func shortManFlags(arg string) []string {
+	if len(arg) < 3 || arg[0] != '-' || arg[1] == '-' {
+		return []string{arg}
+	}
+	var flags []string
+	for _, r := range arg[1:] {
+		flags = append(flags, "-"+string(r))
+		if strings.ContainsRune(manValueFlags, r) {
+			break
+		}
+	}
+	return flags
+}
+
+// ValidateManArgs returns an error if an extra man argument is empty, has a
+// control character, or is a flag that stops man printing the page. Flags
+// with a value (e.g. "-M /opt/man") may be given as two arguments, and
+// short flags may be grouped ("-aw") or have their value attached
+// ("-Tps"), which are checked flag by flag.
+func ValidateManArgs(args []string) error {
+	for _, arg := range args {
+		if arg == "" || strings.ContainsFunc(arg, unicode.IsControl) {
+			return fmt.Errorf("invalid man argument %q", arg)
+		}
+		name, _, _ := strings.Cut(arg, "=")
+		for _, flag := range shortManFlags(name) {
+			if slices.Contains(unsafeManArgs, flag) {
+				return fmt.Errorf("man argument %s is not supported (mantee needs man to print the page)", flag)
+			}
+		}
+	}
+	return nil
+}
+
+// ErrNoManualEntry means man has no page by the requested name (man's "No
+// manual entry for ..."), as opposed to failing to render one
+var ErrNoManualEntry = errors.New("no manual entry")
+
+// FetchManPage retrieves the content of a man page. By default man's bold and
+// underline formatting is kept and decoded into Styles; with opts.Plain it is
+// stripped by col.
+func FetchManPage(section, name string, opts FetchOptions) (*ManPageContent, error) {
+	width := opts.Width
+	if width == 0 {
+		width = DefaultWidth
+	}
+
+	// Use MANWIDTH to control line width. MAN_KEEP_FORMATTING makes man-db
+	// emit formatting even though stdout is not a terminal.
+	// An empty section (unknown from man -k) lets man pick the page
+	pageArgs := []string{name}
+	if section != "" {
+		pageArgs = []string{section, name}
+	}
+	page := strings.Join(pageArgs, " ")
+	// Extra arguments are quoted like the page, so each reaches man as one
+	// argument whatever it contains
+	args := append(slices.Clone(opts.ManArgs), pageArgs...)
+	vars := []string{fmt.Sprintf("MANWIDTH=%d", width)}
+	if !opts.Plain {
+		vars = append(vars, "MAN_KEEP_FORMATTING=1")
+	}
+	// Under opts.Env.Exec the variables are passed to the remote man by
+	// Command; col always runs locally
+	script := man.ShellJoin(opts.Env.Command(vars, args...))
+	if len(opts.Env.Exec) == 0 {
+		script = strings.Join(vars, " ") + " " + script
+	}
+	if opts.Plain {
+		script += " | col " + opts.Col.args()
+	}
+	cmd := execCommand("sh", "-c", script)
+	opts.Env.Apply(cmd)
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
+	start := time.Now()
 	err := cmd.Run()
+	log.Printf("fetch %q: err=%v in %v", script, err, time.Since(start))
 	if err != nil {
+		if strings.Contains(stderr.String(), "No manual entry") {
+			return nil, fmt.Errorf("%w for %s", ErrNoManualEntry, page)
+		}
 		return nil, err
 	}
 
-	content := stdout.String()
+	content := parseContent(stdout.String(), opts)
+	log.Printf("parsed %s: %d lines, %d options, %d sections, %d examples in %v",
+		page, len(content.Lines), len(content.Sections), len(content.ManSections), len(content.Examples), content.ParseDuration)
+	return content, nil
+}
+
+// ParseManPage parses already formatted man output, e.g. from mandoc or
+// groff rather than man itself, the same way FetchManPage parses man's.
+// opts.Plain, opts.Col and opts.SqueezeBlank apply; the rest are unused.
+func ParseManPage(text string, opts FetchOptions) *ManPageContent {
+	return parseContent(text, opts)
+}
+
+// parseContent decodes and parses man's output into a ManPageContent,
+// recording how long that took in ParseDuration
+func parseContent(content string, opts FetchOptions) *ManPageContent {
+	start := time.Now()
+
+	var styles [][]StyledRun
+	if opts.Plain {
+		content = stripControlSequences(content)
+	} else {
+		content, styles = decodeFormatting(content, opts.Col == ColExpandTabs)
+	}
 	lines := strings.Split(content, "\n")
+	lines, styles = squeezeBlankLines(lines, styles, opts.SqueezeBlank)
 
+	manSections := parseManSections(lines)
 	mpc := &ManPageContent{
+		Section:     parseHeaderSection(lines),
 		RawContent:  content,
 		Lines:       lines,
+		Styles:      styles,
 		Sections:    parseOptionSections(lines),
-		ManSections: parseManSections(lines),
+		ManSections: manSections,
+		Examples:    parseExampleBlocks(lines, manSections),
 	}
+	mpc.ParseDuration = time.Since(start)
 
-	return mpc, nil
+	return mpc
+}
+
+// squeezeBlankLines drops trailing blank lines and, with collapse, reduces
+// runs of 3+ blank lines to a single one. styles (if non-nil) is filtered in
+// step so it stays aligned with lines. Sections are parsed afterwards, so
+// their line numbers refer to the squeezed lines.
+func squeezeBlankLines(lines []string, styles [][]StyledRun, collapse bool) ([]string, [][]StyledRun) {
+	isBlank := func(i int) bool { return strings.TrimSpace(lines[i]) == "" }
+
+	// Keep at least one line so an empty page still has a line to show
+	end := len(lines)
+	for end > 1 && isBlank(end-1) {
+		end--
+	}
+
+	var keptLines []string
+	var keptStyles [][]StyledRun
+	for i := 0; i < end; i++ {
+		if collapse && isBlank(i) {
+			// Measure the run of blank lines starting here
+			j := i
+			for j < end && isBlank(j) {
+				j++
+			}
+			if j-i >= 3 {
+				// Keep only the last line of the run
+				i = j - 1
+			}
+		}
+		keptLines = append(keptLines, lines[i])
+		if styles != nil {
+			keptStyles = append(keptStyles, styles[i])
+		}
+	}
+	return keptLines, keptStyles
+}
+
+// LocateManPage runs 'man -w' and returns the path(s) of the source file(s)
+// backing the given man page. The section may be empty.
+func LocateManPage(section, name string, env man.Env) ([]string, error) {
+	args := []string{"-w"}
+	if section != "" {
+		args = append(args, section)
+	}
+	args = append(args, name)
+
+	argv := env.Command(nil, args...)
+	cmd := execCommand(argv[0], argv[1:]...)
+	env.Apply(cmd)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+
+	// man -w prints one path per line (several when multiple pages match)
+	var paths []string
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			paths = append(paths, line)
+		}
+	}
+	return paths, nil
+}
+
+// headerRe matches the title at the start of a man page header line, e.g.
+// "LS(1)   User Commands   LS(1)", capturing the name and section
+var headerRe = regexp.MustCompile(`^\s*([^\s(]+)\(([^)\s]+)\)`)
+
+// parseHeaderSection returns the section from the page's header (its first
+// non-blank line), lowercased to match man -k's spelling (e.g. "3p"). This is
+// the section man actually resolved, which can differ from the one listed by
+// man -k. Returns "" if the header is not recognized.
+func parseHeaderSection(lines []string) string {
+	if m := headerMatch(lines); m != nil {
+		return strings.ToLower(m[2])
+	}
+	return ""
+}
+
+// ParseHeaderName returns the page name from the header, lowercased (e.g.
+// "ls" for "LS(1)"), or "" if the header is not recognized. It names pages
+// that didn't come from man, such as formatted text read from stdin.
+func ParseHeaderName(lines []string) string {
+	if m := headerMatch(lines); m != nil {
+		return strings.ToLower(m[1])
+	}
+	return ""
+}
+
+// headerMatch returns the headerRe submatches of the page's first non-blank
+// line, or nil if it isn't a header
+func headerMatch(lines []string) []string {
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			return headerRe.FindStringSubmatch(line)
+		}
+	}
+	return nil
 }
 
 // parseOptionSections extracts option sections from man page lines
@@ -66,16 +369,6 @@ func FetchManPage(section, name string) (*ManPageContent, error) {
 func parseOptionSections(lines []string) []Section {
 	var sections []Section
 
-	// Option definition pattern: line starting with specific indentation (typically 5-8 spaces)
-	// followed by a dash and option name.
-	// Pattern: 5-8 spaces, then -X (any char) or --word
-	// Using \S to match any non-whitespace for single-char options (handles -@, -%, etc.)
-	optionDefRe := regexp.MustCompile(`^(\s{5,8})(-\S|--[a-zA-Z][-a-zA-Z0-9]*)`)
-
-	// Pattern to detect lines that are lists of multiple --long options (not definitions)
-	// e.g., "--show-error, --stderr, --styled-output, --trace-ascii,"
-	multiOptionListRe := regexp.MustCompile(`--\w+,\s+--\w+,\s+--\w+`)
-
 	// Regex to match major section headers (all caps at start of line)
 	sectionHeaderRe := regexp.MustCompile(`^[A-Z][A-Z ]+$`)
 
@@ -86,21 +379,7 @@ func parseOptionSections(lines []string) []Section {
 		// Check if this line starts an option definition
 		if optionDefRe.MatchString(line) {
 			trimmed := strings.TrimSpace(line)
-			if len(trimmed) == 0 || trimmed[0] != '-' {
-				i++
-				continue
-			}
-
-			// Skip lines that are too long to be option definitions
-			// Description lines are typically much longer than option flag lines
-			if len(trimmed) > maxOptionLineLength {
-				i++
-				continue
-			}
-
-			// Skip lines that look like comma-separated lists of multiple long options
-			// These are typically found in body text, not actual definitions
-			if multiOptionListRe.MatchString(trimmed) {
+			if optionRejection(line) != "" {
 				i++
 				continue
 			}
@@ -116,10 +395,12 @@ func parseOptionSections(lines []string) []Section {
 			section.Option = trimmed
 			i++
 
-			// Skip collecting continuation lines - man pages typically have
-			// options on one line and descriptions on subsequent indented lines
-			// If there are multi-line options (rare), they would have already been
-			// trimmed and joined when we got 'trimmed' above
+			// A signature too long for one line is wrapped after a comma,
+			// continuing with more flags at the same indent
+			for i < len(lines) && strings.HasSuffix(section.Option, ",") && isFlagContinuation(lines[i], optionIndent) {
+				section.Option += " " + strings.TrimSpace(lines[i])
+				i++
+			}
 
 			// Now collect the explanation (more indented lines)
 			var explanationLines []string
@@ -177,6 +458,54 @@ func parseOptionSections(lines []string) []Section {
 	return sections
 }
 
+// isFlagContinuation reports whether line continues a wrapped option
+// signature: another option-like line at the option's indent
+func isFlagContinuation(line string, optionIndent int) bool {
+	indent := len(line) - len(strings.TrimLeft(line, " \t"))
+	return indent == optionIndent && optionDefRe.MatchString(line) && optionRejection(line) == ""
+}
+
+var (
+	// Option definition pattern: line starting with specific indentation (typically 5-8 spaces)
+	// followed by a dash and option name.
+	// Pattern: 5-8 spaces, then -X (any char) or --word
+	// Using \S to match any non-whitespace for single-char options (handles -@, -%, etc.)
+	optionDefRe = regexp.MustCompile(`^(\s{5,8})(-\S|--[a-zA-Z][-a-zA-Z0-9]*)`)
+
+	// Pattern to detect lines that are lists of multiple --long options (not definitions)
+	// e.g., "--show-error, --stderr, --styled-output, --trace-ascii,"
+	multiOptionListRe = regexp.MustCompile(`--\w+,\s+--\w+,\s+--\w+`)
+)
+
+// Reasons an option-like line is not treated as an option definition
+const (
+	rejectWrongIndent = "wrong indent"
+	rejectTooLong     = "too long"
+	rejectOptionList  = "multi-option list"
+)
+
+// optionRejection returns why line cannot start an option definition, or ""
+// if it can
+func optionRejection(line string) string {
+	if !optionDefRe.MatchString(line) {
+		return rejectWrongIndent
+	}
+	trimmed := strings.TrimSpace(line)
+
+	// Lines that are too long to be option definitions
+	// Description lines are typically much longer than option flag lines
+	if len(trimmed) > maxOptionLineLength {
+		return rejectTooLong
+	}
+
+	// Lines that look like comma-separated lists of multiple long options
+	// These are typically found in body text, not actual definitions
+	if multiOptionListRe.MatchString(trimmed) {
+		return rejectOptionList
+	}
+	return ""
+}
+
 // parseManSections extracts major section headers from man page lines
 // These are lines that consist of all uppercase letters (e.g., NAME, SYNOPSIS, DESCRIPTION)
 func parseManSections(lines []string) []ManSection {
@@ -198,9 +527,95 @@ func parseManSections(lines []string) []ManSection {
 		}
 	}
 
+	// Each section ends right before the next one starts; the last runs to EOF
+	for i := range sections {
+		if i+1 < len(sections) {
+			sections[i].EndLine = sections[i+1].StartLine - 1
+		} else {
+			sections[i].EndLine = len(lines) - 1
+		}
+		sections[i].Subsections = parseSubsections(lines, sections[i])
+	}
+
 	return sections
 }
 
+// subsectionIndent is how far man indents subsection headers: less than
+// the section's body text (7 columns for groff, 5 for mandoc)
+const (
+	subsectionIndent    = 3
+	maxSubsectionLength = 60
+)
+
+// parseSubsections extracts the subsection headers within section. A
+// subsection header is a line indented by subsectionIndent that starts a
+// paragraph and is followed by more deeply indented body text, e.g.
+// "   Shell Function Definitions" in bash(1).
+func parseSubsections(lines []string, section ManSection) []ManSection {
+	var subs []ManSection
+	for i := section.StartLine + 1; i <= section.EndLine; i++ {
+		if isSubsectionHeader(lines, i) {
+			subs = append(subs, ManSection{
+				Name:      strings.TrimSpace(lines[i]),
+				Level:     1,
+				StartLine: i,
+			})
+		}
+	}
+
+	// Like sections, each subsection runs up to the next; the last runs to
+	// the end of its section
+	for i := range subs {
+		if i+1 < len(subs) {
+			subs[i].EndLine = subs[i+1].StartLine - 1
+		} else {
+			subs[i].EndLine = section.EndLine
+		}
+	}
+	return subs
+}
+
+// isSubsectionHeader reports whether lines[i] is a subsection header
+func isSubsectionHeader(lines []string, i int) bool {
+	line := lines[i]
+	if indentWidth(line) != subsectionIndent {
+		return false
+	}
+	// Option definitions and list items start with punctuation; headers
+	// are short titles
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" || !unicode.IsLetter([]rune(trimmed)[0]) || len(trimmed) > maxSubsectionLength {
+		return false
+	}
+	// Headers start a paragraph, after a blank line or the section header
+	if strings.TrimSpace(lines[i-1]) != "" && indentWidth(lines[i-1]) != 0 {
+		return false
+	}
+	for _, next := range lines[i+1:] {
+		if strings.TrimSpace(next) != "" {
+			return indentWidth(next) > subsectionIndent
+		}
+	}
+	return false
+}
+
+// indentWidth returns the width of a line's leading whitespace, with tabs
+// advancing to the next multiple of 8 as in a terminal
+func indentWidth(line string) int {
+	width := 0
+	for _, r := range line {
+		switch r {
+		case ' ':
+			width++
+		case '\t':
+			width += 8 - width%8
+		default:
+			return width
+		}
+	}
+	return width
+}
+
 // MatchesQuery checks if a section matches the search query
 // Searches both the option and explanation text (case-insensitive)
 func (s Section) MatchesQuery(query string) bool {
@@ -216,16 +631,59 @@ func (s Section) MatchesQuery(query string) bool {
 // excluding any description text that may follow.
 // e.g., "-F      Display a slash..." -> "-F"
 // e.g., "-r, --recursive   Copy recursively" -> "-r, --recursive"
+// e.g., "-o FILE write output to FILE" -> "-o FILE"
 func ExtractOptionFlags(option string) string {
 	// Options are typically separated from description by multiple spaces
-	// Find where the description starts (2+ consecutive spaces)
-	for i := 0; i < len(option)-1; i++ {
-		if option[i] == ' ' && option[i+1] == ' ' {
-			return strings.TrimSpace(option[:i])
+	// or a tab. Find where the description starts.
+	flags := option
+	if i := strings.IndexByte(option, '\t'); i != -1 {
+		flags = option[:i]
+	}
+	if i := strings.Index(flags, "  "); i != -1 {
+		flags = flags[:i]
+	}
+	flags = strings.TrimSpace(flags)
+
+	// Some pages use a single space; keep flags and their metavars and stop
+	// at the first word that looks like prose
+	tokens := strings.Fields(flags)
+	if len(tokens) == 0 || !strings.HasPrefix(tokens[0], "-") {
+		return flags
+	}
+	n := 0
+	afterFlag := false // Whether the previous token was a flag (a metavar may follow)
+	for n < len(tokens) {
+		token := tokens[n]
+		switch {
+		case strings.HasPrefix(token, "-"):
+			afterFlag = !strings.HasSuffix(token, ",")
+		case afterFlag && isMetavar(token, tokens[n+1:]):
+			afterFlag = false
+		default:
+			return strings.Join(tokens[:n], " ")
 		}
+		n++
+	}
+	return strings.Join(tokens, " ")
+}
+
+// isMetavar reports whether token looks like a flag's argument: FILE,
+// <file>, [FILE], or a lowercase word that ends the flags (followed by a
+// comma, another flag, or nothing) rather than starting a sentence
+func isMetavar(token string, rest []string) bool {
+	word, comma := strings.CutSuffix(token, ",")
+	switch {
+	case word == "":
+		return false
+	case strings.HasPrefix(word, "<") && strings.HasSuffix(word, ">"),
+		strings.HasPrefix(word, "[") && strings.HasSuffix(word, "]"):
+		return true
+	case strings.ToUpper(word) == word && strings.ContainsFunc(word, unicode.IsUpper):
+		return true
+	case strings.ToLower(word) == word && strings.IndexFunc(word, unicode.IsLetter) == 0:
+		return comma || len(rest) == 0 || strings.HasPrefix(rest[0], "-")
 	}
-	// No double-space found, return the whole thing
-	return option
+	return false
 }
 
 // MatchesOption checks if a section's option flags match the search query (case-insensitive)
@@ -240,28 +698,99 @@ func (s Section) MatchesOption(query string) bool {
 
 // MatchesOptionExact checks if a section's option flags exactly match the search query (case-sensitive)
 // Matches individual flags like "-F" or "--force" exactly, not partial matches
-// Strips leading dashes for comparison, so "L" matches "-L" and "location" matches "--location"
+// Strips leading dashes for comparison, so "L" matches "-L" and "location" matches "--location".
+// Arguments are ignored ("color" matches "--color[=WHEN]") and "--[no-]foo"
+// matches both "foo" and "no-foo".
 func (s Section) MatchesOptionExact(query string) bool {
 	if query == "" {
 		return true
 	}
-	flags := ExtractOptionFlags(s.Option)
-	queryNorm := strings.TrimLeft(query, "-")
+	for _, token := range flagTokens(s.Option) {
+		if MatchesFlag(token, query) {
+			return true
+		}
+	}
+	return false
+}
 
-	// Split flags by comma and whitespace to get individual options
-	// e.g., "-r, --recursive" -> ["-r", "--recursive"]
-	parts := strings.FieldsFunc(flags, func(r rune) bool {
+// MatchesOptionGrouped checks if query names one of the short flags in a
+// grouped flag such as "-abc" (matched by "a", "b", or "c"). Single-dash long
+// options like find's "-name" look the same, so this is a fallback for when
+// MatchesOptionExact finds nothing.
+func (s Section) MatchesOptionGrouped(query string) bool {
+	for _, token := range flagTokens(s.Option) {
+		if MatchesGroupedFlag(token, query) {
+			return true
+		}
+	}
+	return false
+}
+
+// flagTokens splits a section's option flags by comma and whitespace into
+// individual flags, e.g. "-r, --recursive" -> ["-r", "--recursive"]
+func flagTokens(option string) []string {
+	return strings.FieldsFunc(ExtractOptionFlags(option), func(r rune) bool {
 		return r == ',' || r == ' '
 	})
+}
 
-	for _, part := range parts {
-		part = strings.TrimSpace(part)
-		if part == "" {
-			continue
+// flagNames returns the names a flag token answers to in exact matching:
+// leading dashes and any argument ("=SIZE", "[=WHEN]") are stripped, and
+// "--[no-]foo" yields both "foo" and "no-foo"
+func flagNames(token string) []string {
+	name := strings.TrimLeft(token, "-")
+	negatable := false
+	if rest, ok := strings.CutPrefix(name, "[no-]"); ok {
+		name = rest
+		negatable = true
+	}
+	if i := strings.IndexAny(name, "=["); i != -1 {
+		name = name[:i]
+	}
+	if name == "" {
+		return nil
+	}
+	if negatable {
+		return []string{name, "no-" + name}
+	}
+	return []string{name}
+}
+
+// groupedFlags returns the individual flags of a grouped short flag token
+// like "-abc" ("a", "b", "c"), or nil for anything else
+func groupedFlags(token string) []string {
+	rest, ok := strings.CutPrefix(token, "-")
+	if !ok || len(rest) < 2 || rest[0] == '-' {
+		return nil
+	}
+	var flags []string
+	for _, r := range rest {
+		if !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9') {
+			return nil
 		}
-		// Strip leading dashes from the flag for comparison (case-sensitive)
-		partNorm := strings.TrimLeft(part, "-")
-		if partNorm == queryNorm {
+		flags = append(flags, string(r))
+	}
+	return flags
+}
+
+// MatchesFlag reports whether a single flag token (e.g. "--color[=WHEN]")
+// exactly matches query, as in Section.MatchesOptionExact
+func MatchesFlag(token, query string) bool {
+	query = strings.TrimLeft(query, "-")
+	for _, name := range flagNames(token) {
+		if name == query {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesGroupedFlag reports whether query names one of the short flags in
+// a grouped token like "-abc", as in Section.MatchesOptionGrouped
+func MatchesGroupedFlag(token, query string) bool {
+	query = strings.TrimLeft(query, "-")
+	for _, flag := range groupedFlags(token) {
+		if flag == query {
 			return true
 		}
 	}
@@ -0,0 +1,905 @@
+package parse
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shadyabhi/mantee/internal/mantest"
+	"github.com/shadyabhi/mantee/man"
+)
+
+// fakeExec fakes this package's execCommand (see mantest.FakeExec)
+func fakeExec(t *testing.T, stdout, stderr string, exitCode int) *[][]string {
+	t.Helper()
+	return mantest.FakeExec(t, &execCommand, stdout, stderr, exitCode)
+}
+
+// TestHelperProcess is not a real test; it is the fake external command
+// spawned by fakeExec
+func TestHelperProcess(t *testing.T) {
+	mantest.HelperProcess()
+}
+
+func TestParseOptionSections(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    []Section
+	}{
+		{
+			fixture: "ls-gnu.txt",
+			want: []Section{
+				{Option: "-a, --all", Explanation: "do not ignore entries starting with .", StartLine: 16, EndLine: 17},
+				{Option: "-A, --almost-all", Explanation: "do not list implied . and ..", StartLine: 19, EndLine: 20},
+				{Option: "--author", Explanation: "with -l, print the author of each file", StartLine: 22, EndLine: 23},
+				{Option: "--block-size=SIZE", Explanation: "with  -l,  scale  sizes  by  SIZE  when printing them; e.g., '--block-size=M'; see SIZE format below", StartLine: 25, EndLine: 27},
+				{Option: "-l     use a long listing format", StartLine: 29, EndLine: 29},
+			},
+		},
+		{
+			fixture: "ls-bsd.txt",
+			want: []Section{
+				{Option: "-@      Display extended attribute keys and sizes in", Explanation: "long (-l) output.", StartLine: 15, EndLine: 16},
+				{Option: "-A      Include directory entries whose names begin", Explanation: "with a dot (‘.’) except for . and ...", StartLine: 18, EndLine: 19},
+				{Option: "--color=when", Explanation: "Output colored escape sequences based on when, which may be set to either always, auto, or never.  always will make ls always output color.", StartLine: 21, EndLine: 25},
+			},
+		},
+		{
+			// Signatures wrapped after a comma keep their aliases; option
+			// lines without a trailing comma stay separate
+			fixture: "wrapped-flags.txt",
+			want: []Section{
+				{Option: "-c, --configuration-file-with-a-long-name=FILE, --cfg=FILE", Explanation: "read settings from FILE instead of ~/.widgetrc", StartLine: 6, EndLine: 8},
+				{Option: "-q, --quiet, --silent", Explanation: "suppress normal output", StartLine: 10, EndLine: 12},
+				{Option: "-n", StartLine: 14, EndLine: 14},
+				{Option: "-v     be verbose", StartLine: 15, EndLine: 15},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			lines := strings.Split(mantest.ReadFixture(t, tt.fixture), "\n")
+			got := parseOptionSections(lines)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseOptionSections() =\n%#v\nwant\n%#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseManSections(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    []ManSection
+	}{
+		{
+			fixture: "ls-gnu.txt",
+			want: []ManSection{
+				{Name: "NAME", StartLine: 2, EndLine: 4},
+				{Name: "SYNOPSIS", StartLine: 5, EndLine: 7},
+				{Name: "DESCRIPTION", StartLine: 8, EndLine: 30},
+				{Name: "AUTHOR", StartLine: 31, EndLine: 33},
+				{Name: "SEE ALSO", StartLine: 34, EndLine: 39},
+			},
+		},
+		{
+			fixture: "ls-bsd.txt",
+			want: []ManSection{
+				{Name: "NAME", StartLine: 2, EndLine: 4},
+				{Name: "SYNOPSIS", StartLine: 5, EndLine: 8},
+				{Name: "DESCRIPTION", StartLine: 9, EndLine: 26},
+				{Name: "EXIT STATUS", StartLine: 27, EndLine: 31},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			lines := strings.Split(mantest.ReadFixture(t, tt.fixture), "\n")
+			got := parseManSections(lines)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseManSections() =\n%#v\nwant\n%#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseManSubsections(t *testing.T) {
+	lines := []string{
+		"NAME",
+		"       bash - GNU Bourne-Again SHell",
+		"",
+		"SHELL GRAMMAR",
+		"   Simple Commands",
+		"       A simple command is a sequence of optional variable",
+		"       assignments followed by blank-separated words.",
+		"",
+		"   Pipelines",
+		"       A pipeline is a sequence of one or more commands.",
+		"",
+		"   -x  not a header",
+		"       list item body",
+		"",
+		"   Trailing text without a body",
+		"",
+		"OPTIONS",
+		"       -c        Read commands from the first argument.",
+	}
+	sections := parseManSections(lines)
+	if len(sections) != 3 {
+		t.Fatalf("parseManSections() returned %d sections, want 3", len(sections))
+	}
+	if sections[0].Subsections != nil || sections[2].Subsections != nil {
+		t.Errorf("NAME and OPTIONS subsections = %v, %v, want none", sections[0].Subsections, sections[2].Subsections)
+	}
+
+	want := []ManSection{
+		{Name: "Simple Commands", Level: 1, StartLine: 4, EndLine: 7},
+		{Name: "Pipelines", Level: 1, StartLine: 8, EndLine: 15},
+	}
+	if got := sections[1].Subsections; !reflect.DeepEqual(got, want) {
+		t.Errorf("SHELL GRAMMAR subsections =\n%#v\nwant\n%#v", got, want)
+	}
+}
+
+func TestParseHeaderSection(t *testing.T) {
+	tests := []struct {
+		lines []string
+		want  string
+	}{
+		{[]string{"LS(1)      User Commands      LS(1)"}, "1"},
+		{[]string{"", "PRINTF(3P)   POSIX Programmer's Manual   PRINTF(3P)"}, "3p"},
+		{[]string{"git-log(1)   Git Manual   git-log(1)"}, "1"},
+		{[]string{"NAME", "     ls - list"}, ""},
+		{nil, ""},
+	}
+
+	for _, tt := range tests {
+		if got := parseHeaderSection(tt.lines); got != tt.want {
+			t.Errorf("parseHeaderSection(%q) = %q, want %q", tt.lines, got, tt.want)
+		}
+	}
+}
+
+func TestParseHeaderName(t *testing.T) {
+	tests := []struct {
+		lines []string
+		want  string
+	}{
+		{[]string{"LS(1)      User Commands      LS(1)"}, "ls"},
+		{[]string{"", "git-log(1)   Git Manual   git-log(1)"}, "git-log"},
+		{[]string{"NAME", "     ls - list"}, ""},
+		{nil, ""},
+	}
+
+	for _, tt := range tests {
+		if got := ParseHeaderName(tt.lines); got != tt.want {
+			t.Errorf("ParseHeaderName(%q) = %q, want %q", tt.lines, got, tt.want)
+		}
+	}
+}
+
+func TestParseManPage(t *testing.T) {
+	content := ParseManPage(mantest.ReadFixture(t, "ls-gnu.txt"), FetchOptions{Plain: true})
+
+	if len(content.Sections) != 5 {
+		t.Errorf("got %d option sections, want 5", len(content.Sections))
+	}
+	if len(content.ManSections) != 5 {
+		t.Errorf("got %d man sections, want 5", len(content.ManSections))
+	}
+}
+
+func TestExtractOptionFlags(t *testing.T) {
+	tests := []struct {
+		option string
+		want   string
+	}{
+		{"-a, --all", "-a, --all"},
+		{"-l     use a long listing format", "-l"},
+		{"-r, --recursive   Copy recursively", "-r, --recursive"},
+		{"--block-size=SIZE", "--block-size=SIZE"},
+		// Tab-separated descriptions
+		{"-l\tuse a long listing format", "-l"},
+		{"-r, --recursive\tcopy recursively", "-r, --recursive"},
+		// Single-space-separated descriptions
+		{"-l use a long listing format", "-l"},
+		{"-o FILE write output to FILE", "-o FILE"},
+		{"-v Verbose output.", "-v"},
+		{"-a Show ALL files", "-a"},
+		{"-r, --recursive copy recursively", "-r, --recursive"},
+		// Metavars are kept with their flags
+		{"-e PATTERNS, --regexp=PATTERNS", "-e PATTERNS, --regexp=PATTERNS"},
+		{"-f file", "-f file"},
+		{"-o file, --output=file", "-o file, --output=file"},
+		{"-I <dir> add an include directory", "-I <dir>"},
+		{"--color[=WHEN]", "--color[=WHEN]"},
+	}
+
+	for _, tt := range tests {
+		if got := ExtractOptionFlags(tt.option); got != tt.want {
+			t.Errorf("ExtractOptionFlags(%q) = %q, want %q", tt.option, got, tt.want)
+		}
+	}
+}
+
+func TestMatchesOptionExact(t *testing.T) {
+	tests := []struct {
+		option string
+		query  string
+		want   bool
+	}{
+		{"-a, --all", "a", true},
+		{"-a, --all", "-a", true},
+		{"-a, --all", "all", true},
+		{"-a, --all", "A", false},
+		{"-A, --almost-all", "all", false},
+		{"-l     use a long listing format", "l", true},
+		{"-l     use a long listing format", "long", false},
+		// Arguments are not part of the flag name (ls, grep)
+		{"--block-size=SIZE", "block-size", true},
+		{"--color[=WHEN]", "--color", true},
+		{"--color[=WHEN]", "WHEN", false},
+		{"-e PATTERNS, --regexp=PATTERNS", "regexp", true},
+		{"-A NUM, --after-context=NUM", "A", true},
+		// Negatable long options (e.g. git, rsync)
+		{"--[no-]verify", "verify", true},
+		{"--[no-]verify", "--no-verify", true},
+		{"--[no-]verify", "no-", false},
+		// Grouped short flags only match via MatchesOptionGrouped
+		{"-abc", "b", false},
+		{"-abc", "abc", true},
+	}
+
+	for _, tt := range tests {
+		s := Section{Option: tt.option}
+		if got := s.MatchesOptionExact(tt.query); got != tt.want {
+			t.Errorf("MatchesOptionExact(%q, %q) = %v, want %v", tt.option, tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestMatchesOptionGrouped(t *testing.T) {
+	tests := []struct {
+		option string
+		query  string
+		want   bool
+	}{
+		{"-abc", "b", true},
+		{"-abc", "-c", true},
+		{"-abc", "d", false},
+		{"-1", "1", false}, // A single flag is not a group
+		{"--all", "a", false},
+		{"-name pattern", "n", true}, // Indistinguishable from a group, hence only a fallback
+		{"-o[FILE]", "o", false},
+	}
+
+	for _, tt := range tests {
+		s := Section{Option: tt.option}
+		if got := s.MatchesOptionGrouped(tt.query); got != tt.want {
+			t.Errorf("MatchesOptionGrouped(%q, %q) = %v, want %v", tt.option, tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestSectionIndex(t *testing.T) {
+	var sections []Section
+	for _, fixture := range []string{"ls-gnu.txt", "ls-bsd.txt"} {
+		sections = append(sections, parseOptionSections(strings.Split(mantest.ReadFixture(t, fixture), "\n"))...)
+	}
+	index := NewSectionIndex(sections)
+
+	// The index must agree with the per-Section methods
+	matching := func(match func(Section) bool) []int {
+		var indices []int
+		for i, s := range sections {
+			if match(s) {
+				indices = append(indices, i)
+			}
+		}
+		return indices
+	}
+	for _, query := range []string{"", "a", "A", "-a", "all", "--COLOR", "color", "size", "block-size", "l", "xyzzy"} {
+		checks := []struct {
+			name string
+			got  []int
+			want []int
+		}{
+			{"MatchQuery", index.MatchQuery(query, false), matching(func(s Section) bool { return s.MatchesQuery(query) })},
+			{"MatchOption", index.MatchOption(query, false), matching(func(s Section) bool { return s.MatchesOption(query) })},
+			{"MatchOptionExact", index.MatchOptionExact(query), matching(func(s Section) bool { return s.MatchesOptionExact(query) })},
+			{"MatchOptionGrouped", index.MatchOptionGrouped(query), matching(func(s Section) bool { return s.MatchesOptionGrouped(query) })},
+			{"MatchDescription", index.MatchDescription(query, false), matching(func(s Section) bool { return s.MatchesDescription(query) })},
+		}
+		for _, c := range checks {
+			if !reflect.DeepEqual(c.got, c.want) {
+				t.Errorf("%s(%q) = %v, want %v", c.name, query, c.got, c.want)
+			}
+		}
+	}
+}
+
+func TestSectionIndexCaseSensitive(t *testing.T) {
+	index := NewSectionIndex([]Section{
+		{Option: "-C", Explanation: "list entries by columns"},
+		{Option: "-c", Explanation: "sort by ctime; see Columns"},
+	})
+
+	tests := []struct {
+		name string
+		got  []int
+		want []int
+	}{
+		{"MatchOption", index.MatchOption("C", true), []int{0}},
+		{"MatchOption folded", index.MatchOption("C", false), []int{0, 1}},
+		{"MatchQuery", index.MatchQuery("Columns", true), []int{1}},
+		{"MatchDescription", index.MatchDescription("columns", true), []int{0}},
+	}
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.got, tt.want) {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestCaseSensitive(t *testing.T) {
+	for query, want := range map[string]bool{"color": false, "Color": true, "-C": true, "--all": false, "": false, "é": false, "É": true,
+		`\S+`: false, `\W`: false, `a\Bc`: false, `\sFoo`: true, `\\S`: true} {
+		if got := CaseSensitive(query); got != want {
+			t.Errorf("CaseSensitive(%q) = %v, want %v", query, got, want)
+		}
+	}
+}
+
+func TestFormatOptionTable(t *testing.T) {
+	sections := []Section{
+		{Option: "-a, --all", Explanation: "do not ignore   entries\n starting with ."},
+		{Option: "-l     use a long listing format"},
+		{Option: "--color=when", Explanation: strings.Repeat("word ", 30)},
+		{Option: "--bare"},
+	}
+
+	want := "-a, --all     do not ignore entries starting with .\n" +
+		"-l            use a long listing format\n" +
+		"--color=when  " + strings.Repeat("word ", 15) + "wo...\n" +
+		"--bare\n"
+	if got := FormatOptionTable(sections); got != want {
+		t.Errorf("FormatOptionTable() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestParseExampleBlocks(t *testing.T) {
+	lines := []string{
+		"DESCRIPTION",
+		"           not an example",
+		"EXAMPLES",
+		"       List files:",
+		"",
+		"           ls -l",
+		"           ls -la \\",
+		"               /tmp",
+		"",
+		"           ls -R",
+		"",
+		"       Or with a prompt:",
+		"       $ ls",
+		"SEE ALSO",
+		"       dir(1)",
+	}
+	sections := parseManSections(lines)
+
+	blocks := parseExampleBlocks(lines, sections)
+	want := []CodeBlock{{StartLine: 5, EndLine: 9}, {StartLine: 12, EndLine: 12}}
+	if !reflect.DeepEqual(blocks, want) {
+		t.Fatalf("parseExampleBlocks() = %v, want %v", blocks, want)
+	}
+
+	wantCode := "ls -l\nls -la \\\n    /tmp\n\nls -R"
+	if got := blocks[0].Code(lines); got != wantCode {
+		t.Errorf("Code() = %q, want %q", got, wantCode)
+	}
+}
+
+func TestMatchingLines(t *testing.T) {
+	content := &ManPageContent{Lines: []string{
+		"NAME",
+		"       ssh - OpenSSH remote login client",
+		"       -J destination",
+		"              Connect via a ProxyJump host.",
+		"       See ProxyJump in ssh_config(5).",
+	}}
+
+	if got, want := content.MatchingLines("proxyjump", false), []int{3, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("MatchingLines(proxyjump) = %v, want %v", got, want)
+	}
+	if got := content.MatchingLines("scp", false); got != nil {
+		t.Errorf("MatchingLines(scp) = %v, want nil", got)
+	}
+	if got := content.MatchingLines("", false); got != nil {
+		t.Errorf("MatchingLines(\"\") = %v, want nil", got)
+	}
+	if got, want := content.MatchingLines("ProxyJump", true), []int{3, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("MatchingLines(ProxyJump, true) = %v, want %v", got, want)
+	}
+	if got := content.MatchingLines("proxyjump", true); got != nil {
+		t.Errorf("MatchingLines(proxyjump, true) = %v, want nil", got)
+	}
+}
+
+func TestRejectedOptions(t *testing.T) {
+	lines := []string{
+		"OPTIONS",
+		"       -a, --all",
+		"              do not ignore entries starting with .",
+		"  -x     not indented like the other options",
+		"       --show-error, --stderr, --styled-output, --trace-ascii, --verbose",
+		"       --stderr, --trace, --verbose, --quiet",
+		"       -1     list one file per line and keep going for a while past sixty",
+		"              - a bullet, not an option",
+	}
+
+	want := []RejectedOption{
+		{Line: 3, Text: "-x     not indented like the other options", Reason: "wrong indent"},
+		{Line: 4, Text: "--show-error, --stderr, --styled-output, --trace-ascii, --verbose", Reason: "too long"},
+		{Line: 5, Text: "--stderr, --trace, --verbose, --quiet", Reason: "multi-option list"},
+		{Line: 6, Text: "-1     list one file per line and keep going for a while past sixty", Reason: "too long"},
+	}
+	if got := RejectedOptions(lines); !reflect.DeepEqual(got, want) {
+		t.Errorf("RejectedOptions() =\n%#v\nwant\n%#v", got, want)
+	}
+}
+
+func TestFormatParseReport(t *testing.T) {
+	content := parseContent("OPTIONS\n       -a, --all\n              do not ignore entries\n  -x     misplaced\n       -l     long format\n", FetchOptions{Plain: true})
+
+	report := FormatParseReport(content)
+	for _, want := range []string{
+		"Man sections (1):",
+		"0-4      OPTIONS",
+		"Option sections (2):",
+		"1-2      -a, --all",
+		"flags: -a, --all",
+		"explanation: do not ignore entries",
+		"explanation: long format",
+		"Rejected option-like lines (1):",
+		"3  wrong indent       -x     misplaced",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report missing %q:\n%s", want, report)
+		}
+	}
+}
+
+// syntheticSections returns n option sections resembling a large page like gcc
+func syntheticSections(n int) []Section {
+	sections := make([]Section, n)
+	for i := range sections {
+		sections[i] = Section{
+			Option:      fmt.Sprintf("-f%d, --Feature-Option-%d=VALUE", i, i),
+			Explanation: fmt.Sprintf("Enable Feature number %d. This Option controls Code Generation for the target and may be repeated.", i),
+		}
+	}
+	return sections
+}
+
+// BenchmarkMatchQuerySections measures searching 1000 sections via the
+// per-Section methods, which lowercase every section on each call
+func BenchmarkMatchQuerySections(b *testing.B) {
+	sections := syntheticSections(1000)
+	for b.Loop() {
+		var indices []int
+		for i, s := range sections {
+			if s.MatchesQuery("generation") {
+				indices = append(indices, i)
+			}
+		}
+	}
+}
+
+// BenchmarkMatchQueryIndex measures the same search via a SectionIndex
+func BenchmarkMatchQueryIndex(b *testing.B) {
+	index := NewSectionIndex(syntheticSections(1000))
+	for b.Loop() {
+		index.MatchQuery("generation", false)
+	}
+}
+
+// syntheticPage returns formatted man output of roughly n lines: a header
+// followed by option entries whose flags are bold via overstrike
+func syntheticPage(n int) string {
+	var b strings.Builder
+	b.WriteString("GCC(1)                              GNU                              GCC(1)\n\nOPTIONS\n")
+	for i := 0; i < n/3; i++ {
+		flag := fmt.Sprintf("-f%d, --feature-option-%d", i, i)
+		b.WriteString("       ")
+		for _, r := range flag {
+			fmt.Fprintf(&b, "%c\b%c", r, r)
+		}
+		fmt.Fprintf(&b, "\n              Enable feature number %d for the target; may be repeated.\n\n", i)
+	}
+	return b.String()
+}
+
+// BenchmarkParseContent measures decoding and parsing a 50k-line page
+func BenchmarkParseContent(b *testing.B) {
+	raw := syntheticPage(50000)
+	for b.Loop() {
+		parseContent(raw, FetchOptions{})
+	}
+}
+
+// TestParseContentLargePage guards against parsing regressing to something
+// super-linear on very large pages. The bound is generous so it holds on slow
+// CI machines; a linear parser takes well under a second.
+func TestParseContentLargePage(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping large page parse in short mode")
+	}
+
+	content := parseContent(syntheticPage(50000), FetchOptions{})
+	if len(content.Lines) < 49000 {
+		t.Fatalf("got %d lines, want ~50000", len(content.Lines))
+	}
+	if len(content.Sections) != 50000/3 {
+		t.Errorf("got %d option sections, want %d", len(content.Sections), 50000/3)
+	}
+	if content.ParseDuration <= 0 {
+		t.Errorf("ParseDuration = %v, want > 0", content.ParseDuration)
+	}
+	if limit := 5 * time.Second; content.ParseDuration > limit {
+		t.Errorf("parsing 50k lines took %v, want under %v", content.ParseDuration, limit)
+	}
+}
+
+func TestFetchManPage(t *testing.T) {
+	calls := fakeExec(t, mantest.ReadFixture(t, "ls-gnu.txt"), "", 0)
+
+	content, err := FetchManPage("1", "ls", FetchOptions{})
+	if err != nil {
+		t.Fatalf("FetchManPage() error = %v", err)
+	}
+
+	if len(*calls) != 1 || !strings.Contains(strings.Join((*calls)[0], " "), "MANWIDTH=80 MAN_KEEP_FORMATTING=1 man 1 ls") {
+		t.Errorf("unexpected command: %v", *calls)
+	}
+	if len(content.Sections) != 5 {
+		t.Errorf("got %d option sections, want 5", len(content.Sections))
+	}
+	if content.Section != "1" {
+		t.Errorf("Section = %q, want %q", content.Section, "1")
+	}
+	if len(content.ManSections) != 5 {
+		t.Errorf("got %d man sections, want 5", len(content.ManSections))
+	}
+}
+
+func TestFetchManPageNoSection(t *testing.T) {
+	calls := fakeExec(t, "", "", 0)
+
+	if _, err := FetchManPage("", "lsblk", FetchOptions{}); err != nil {
+		t.Fatalf("FetchManPage() error = %v", err)
+	}
+	if !strings.HasSuffix(strings.Join((*calls)[0], " "), "MAN_KEEP_FORMATTING=1 man lsblk") {
+		t.Errorf("empty section not omitted: %v", *calls)
+	}
+}
+
+func TestFetchManPageWidth(t *testing.T) {
+	calls := fakeExec(t, "", "", 0)
+
+	if _, err := FetchManPage("1", "ls", FetchOptions{Width: 120}); err != nil {
+		t.Fatalf("FetchManPage() error = %v", err)
+	}
+	if !strings.Contains(strings.Join((*calls)[0], " "), "MANWIDTH=120") {
+		t.Errorf("width not applied: %v", *calls)
+	}
+}
+
+func TestFetchManPageQuotesName(t *testing.T) {
+	calls := fakeExec(t, "", "", 0)
+
+	if _, err := FetchManPage("1", "ls; reboot", FetchOptions{}); err != nil {
+		t.Fatalf("FetchManPage() error = %v", err)
+	}
+	if !strings.HasSuffix(strings.Join((*calls)[0], " "), "man 1 'ls; reboot'") {
+		t.Errorf("page name not quoted: %v", *calls)
+	}
+}
+
+func TestFetchManPageManArgs(t *testing.T) {
+	calls := fakeExec(t, "", "", 0)
+
+	opts := FetchOptions{ManArgs: []string{"-M", "/opt/my man"}}
+	if _, err := FetchManPage("1", "ls", opts); err != nil {
+		t.Fatalf("FetchManPage() error = %v", err)
+	}
+	if !strings.HasSuffix(strings.Join((*calls)[0], " "), "man -M '/opt/my man' 1 ls") {
+		t.Errorf("man args not passed before the page: %v", *calls)
+	}
+}
+
+func TestValidateManArgs(t *testing.T) {
+	tests := []struct {
+		args    []string
+		wantErr bool
+	}{
+		{nil, false},
+		{[]string{"-a"}, false},
+		{[]string{"-M", "/opt/man"}, false},
+		{[]string{"--manpath=/opt/man"}, false},
+		{[]string{"-w"}, true},
+		{[]string{"--html=firefox"}, true},
+		{[]string{"-k"}, true},
+		{[]string{""}, true},
+		{[]string{"-a\n"}, true},
+		{[]string{"-aw"}, true},
+		{[]string{"-ak"}, true},
+		{[]string{"-Tps"}, true},
+		{[]string{"-Hfirefox"}, true},
+		{[]string{"-Pcat"}, false},
+		{[]string{"-M/opt/wman"}, false},
+		{[]string{"-aM/opt/man"}, false},
+	}
+	for _, tt := range tests {
+		if err := ValidateManArgs(tt.args); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateManArgs(%q) error = %v, wantErr %v", tt.args, err, tt.wantErr)
+		}
+	}
+}
+
+func TestFetchManPageExec(t *testing.T) {
+	calls := fakeExec(t, "", "", 0)
+
+	env := man.Env{Exec: []string{"ssh", "host"}}
+	if _, err := FetchManPage("1", "ls", FetchOptions{Env: env, Plain: true}); err != nil {
+		t.Fatalf("FetchManPage() error = %v", err)
+	}
+	want := "ssh host env MANWIDTH=80 man 1 ls | col -b"
+	if got := strings.Join((*calls)[0], " "); !strings.HasSuffix(got, want) {
+		t.Errorf("command = %q, want suffix %q", got, want)
+	}
+}
+
+func TestFetchManPageColMode(t *testing.T) {
+	calls := fakeExec(t, "", "", 0)
+
+	if _, err := FetchManPage("5", "resolv.conf", FetchOptions{Col: ColExpandTabs, Plain: true}); err != nil {
+		t.Fatalf("FetchManPage() error = %v", err)
+	}
+	if !strings.HasSuffix(strings.Join((*calls)[0], " "), "| col -bx") {
+		t.Errorf("col mode not applied: %v", *calls)
+	}
+}
+
+func TestFetchManPageFormatting(t *testing.T) {
+	fakeExec(t, "N\bNA\bAM\bME\bE\n     l\bls\bs - _\bl_\bi_\bs_\bt\n", "", 0)
+
+	content, err := FetchManPage("1", "ls", FetchOptions{})
+	if err != nil {
+		t.Fatalf("FetchManPage() error = %v", err)
+	}
+	if want := "NAME\n     ls - list\n"; content.RawContent != want {
+		t.Errorf("RawContent = %q, want %q", content.RawContent, want)
+	}
+	if len(content.ManSections) != 1 || content.ManSections[0].Name != "NAME" {
+		t.Errorf("ManSections = %+v, want NAME", content.ManSections)
+	}
+	wantStyles := [][]StyledRun{
+		{{Start: 0, End: 4, Style: StyleBold}},
+		{{Start: 5, End: 7, Style: StyleBold}, {Start: 10, End: 14, Style: StyleUnderline}},
+	}
+	if !reflect.DeepEqual(content.Styles, wantStyles) {
+		t.Errorf("Styles = %+v, want %+v", content.Styles, wantStyles)
+	}
+}
+
+func TestStripControlSequences(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"plain\ttext\n", "plain\ttext\n"},
+		{"\x1b[1mNAME\x1b[0m", "NAME"},
+		{"-a, --all\x1b[K", "-a, --all"},
+		{"bell\x07 and del\x7f", "bell and del"},
+		{"trailing escape\x1b", "trailing escape"},
+		{"speci\u2010fied", "speci\u2010fied"},
+		{"\x1b]0;title\x07text", "text"},
+		{"see \x1b]8;;man:ls(1)\x1b\\ls(1)\x1b]8;;\x1b\\ too", "see ls(1) too"},
+		{"\x1bPpayload\x1b\\after", "after"},
+		{"unterminated \x1b]8;;url", "unterminated "},
+	}
+
+	for _, tt := range tests {
+		if got := stripControlSequences(tt.raw); got != tt.want {
+			t.Errorf("stripControlSequences(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestFetchManPagePlainStripsEscapes(t *testing.T) {
+	// col -b leaves SGR escapes from groff untouched
+	fakeExec(t, "\x1b[1mNAME\x1b[0m\n     ls - list\x1b[K\n", "", 0)
+
+	content, err := FetchManPage("1", "ls", FetchOptions{Plain: true})
+	if err != nil {
+		t.Fatalf("FetchManPage() error = %v", err)
+	}
+	want := []string{"NAME", "     ls - list"}
+	if !reflect.DeepEqual(content.Lines, want) {
+		t.Errorf("Lines = %q, want %q", content.Lines, want)
+	}
+	// Only printable text is left, so display widths line up with man's layout
+	for _, line := range content.Lines {
+		if strings.ContainsFunc(line, isStrayControl) {
+			t.Errorf("line %q still contains control characters", line)
+		}
+	}
+}
+
+func TestFetchManPagePlain(t *testing.T) {
+	calls := fakeExec(t, "NAME\n", "", 0)
+
+	content, err := FetchManPage("1", "ls", FetchOptions{Plain: true})
+	if err != nil {
+		t.Fatalf("FetchManPage() error = %v", err)
+	}
+	if !strings.HasSuffix(strings.Join((*calls)[0], " "), "| col -b") {
+		t.Errorf("plain mode should pipe through col -b: %v", *calls)
+	}
+	if content.Styles != nil {
+		t.Errorf("Styles = %+v, want nil", content.Styles)
+	}
+}
+
+func TestSqueezeBlankLines(t *testing.T) {
+	lines := []string{"NAME", "", "", "", "", "     ls", "", "OPTIONS", "", "", "   ", ""}
+	tests := []struct {
+		collapse bool
+		want     []string
+	}{
+		{false, []string{"NAME", "", "", "", "", "     ls", "", "OPTIONS"}},
+		{true, []string{"NAME", "", "     ls", "", "OPTIONS"}},
+	}
+
+	for _, tt := range tests {
+		styles := make([][]StyledRun, len(lines))
+		for i := range styles {
+			styles[i] = []StyledRun{{Start: i}} // Tag each line's runs with its index
+		}
+
+		got, gotStyles := squeezeBlankLines(lines, styles, tt.collapse)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("squeezeBlankLines(collapse=%v) = %q, want %q", tt.collapse, got, tt.want)
+		}
+		if len(gotStyles) != len(got) {
+			t.Fatalf("got %d style entries for %d lines", len(gotStyles), len(got))
+		}
+		for i, runs := range gotStyles {
+			if lines[runs[0].Start] != got[i] {
+				t.Errorf("styles misaligned at line %d", i)
+			}
+		}
+	}
+}
+
+func TestFetchManPageSqueezeBlank(t *testing.T) {
+	fakeExec(t, "NAME\n     ls\n\n\n\n\nOPTIONS\n     -a     all\n\n\n", "", 0)
+
+	content, err := FetchManPage("1", "ls", FetchOptions{SqueezeBlank: true})
+	if err != nil {
+		t.Fatalf("FetchManPage() error = %v", err)
+	}
+	if len(content.Lines) != 5 {
+		t.Errorf("got %d lines, want 5: %q", len(content.Lines), content.Lines)
+	}
+	// Section line numbers refer to the squeezed lines
+	if got := content.ManSections[1]; got.Name != "OPTIONS" || got.StartLine != 3 {
+		t.Errorf("OPTIONS section = %+v, want StartLine 3", got)
+	}
+	if len(content.Sections) != 1 || content.Sections[0].StartLine != 4 {
+		t.Errorf("option sections = %+v, want one at line 4", content.Sections)
+	}
+}
+
+func TestDecodeLine(t *testing.T) {
+	tests := []struct {
+		name       string
+		raw        string
+		expandTabs bool
+		wantText   string
+		wantRuns   []StyledRun
+	}{
+		{name: "plain", raw: "plain text", wantText: "plain text"},
+		{name: "bold", raw: "a\bab\bb c", wantText: "ab c", wantRuns: []StyledRun{{0, 2, StyleBold}}},
+		{name: "underline", raw: "x _\by", wantText: "x y", wantRuns: []StyledRun{{2, 3, StyleUnderline}}},
+		{name: "underline after char", raw: "y\b_", wantText: "y", wantRuns: []StyledRun{{0, 1, StyleUnderline}}},
+		{name: "bold underline", raw: "_\ba\ba", wantText: "a", wantRuns: []StyledRun{{0, 1, StyleBold | StyleUnderline}}},
+		{name: "bullet overstrike", raw: "+\bo item", wantText: "o item"},
+		{name: "leading backspace", raw: "\bx", wantText: "x"},
+		{name: "sgr", raw: "\x1b[1mNAME\x1b[0m and \x1b[4mfile\x1b[24m", wantText: "NAME and file",
+			wantRuns: []StyledRun{{0, 4, StyleBold}, {9, 13, StyleUnderline}}},
+		{name: "sgr color ignored", raw: "\x1b[31mred\x1b[m", wantText: "red"},
+		{name: "tabs kept", raw: "a\tb", wantText: "a\tb"},
+		{name: "tabs expanded", raw: "a\tb", expandTabs: true, wantText: "a       b"},
+		{name: "multibyte", raw: "\u00e9\b\u00e9x", wantText: "\u00e9x", wantRuns: []StyledRun{{0, 2, StyleBold}}},
+		{name: "stray controls", raw: "a\x07b\x7fc\x1b]0;title\x07", wantText: "abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, runs := decodeLine(tt.raw, tt.expandTabs)
+			if text != tt.wantText {
+				t.Errorf("text = %q, want %q", text, tt.wantText)
+			}
+			if !reflect.DeepEqual(runs, tt.wantRuns) {
+				t.Errorf("runs = %+v, want %+v", runs, tt.wantRuns)
+			}
+		})
+	}
+}
+
+func TestFetchManPageEnv(t *testing.T) {
+	t.Setenv("HELPER_EXPAND", "1")
+	fakeExec(t, "$MANPATH $LANG", "", 0)
+
+	content, err := FetchManPage("1", "ls", FetchOptions{Env: man.Env{ManPath: "/opt/man", Lang: "fr_FR.UTF-8"}})
+	if err != nil {
+		t.Fatalf("FetchManPage() error = %v", err)
+	}
+	if want := "/opt/man fr_FR.UTF-8"; content.RawContent != want {
+		t.Errorf("child environment = %q, want %q", content.RawContent, want)
+	}
+}
+
+func TestLocateManPageEnv(t *testing.T) {
+	t.Setenv("HELPER_EXPAND", "1")
+	fakeExec(t, "$MANPATH/man1/ls.1", "", 0)
+
+	paths, err := LocateManPage("1", "ls", man.Env{ManPath: "/opt/man"})
+	if err != nil {
+		t.Fatalf("LocateManPage() error = %v", err)
+	}
+	if want := []string{"/opt/man/man1/ls.1"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("LocateManPage() = %v, want %v", paths, want)
+	}
+}
+
+func TestFetchManPageError(t *testing.T) {
+	fakeExec(t, "", "No manual entry for nope\n", 1)
+
+	_, err := FetchManPage("", "nope", FetchOptions{})
+	if !errors.Is(err, ErrNoManualEntry) {
+		t.Errorf("FetchManPage() error = %v, want ErrNoManualEntry", err)
+	}
+}
+
+func TestFetchManPageFailure(t *testing.T) {
+	fakeExec(t, "", "troff: fatal error\n", 2)
+
+	_, err := FetchManPage("1", "broken", FetchOptions{})
+	if err == nil || errors.Is(err, ErrNoManualEntry) {
+		t.Errorf("FetchManPage() error = %v, want a non-ErrNoManualEntry error", err)
+	}
+}
+
+func TestLocateManPage(t *testing.T) {
+	calls := fakeExec(t, "/usr/share/man/man1/printf.1.gz\n/usr/share/man/man3/printf.3.gz\n", "", 0)
+
+	paths, err := LocateManPage("", "printf", man.Env{})
+	if err != nil {
+		t.Fatalf("LocateManPage() error = %v", err)
+	}
+
+	want := []string{"/usr/share/man/man1/printf.1.gz", "/usr/share/man/man3/printf.3.gz"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("LocateManPage() = %v, want %v", paths, want)
+	}
+	if got := (*calls)[0]; !reflect.DeepEqual(got, []string{"man", "-w", "printf"}) {
+		t.Errorf("unexpected command: %v", got)
+	}
+}
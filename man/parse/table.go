@@ -0,0 +1,53 @@
+package parse
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// maxFlagColumnWidth caps the flags column of an option table so one
+	// long signature doesn't push every explanation to the right
+	maxFlagColumnWidth = 30
+	// maxSummaryLength is the longest explanation shown in an option table
+	maxSummaryLength = 80
+)
+
+// FormatOptionTable renders sections as a plain-text cheatsheet: one line
+// per option with its flags and a one-line summary of the explanation
+func FormatOptionTable(sections []Section) string {
+	width := 0
+	for _, s := range sections {
+		width = max(width, len(ExtractOptionFlags(s.Option)))
+	}
+	width = min(width, maxFlagColumnWidth)
+
+	var b strings.Builder
+	for _, s := range sections {
+		flags := ExtractOptionFlags(s.Option)
+		summary := SummarizeExplanation(s)
+		if summary == "" {
+			b.WriteString(flags + "\n")
+			continue
+		}
+		fmt.Fprintf(&b, "%-*s  %s\n", width, flags, summary)
+	}
+	return b.String()
+}
+
+// SummarizeExplanation returns the explanation on a single line with runs of
+// whitespace collapsed, truncated to maxSummaryLength. Options written on one
+// line with their description (e.g. BSD "-l     long format") use the text
+// after the flags.
+func SummarizeExplanation(s Section) string {
+	text := s.Explanation
+	if flags := ExtractOptionFlags(s.Option); flags != s.Option {
+		text = strings.TrimSpace(s.Option[len(flags):]) + " " + text
+	}
+	text = strings.Join(strings.Fields(text), " ")
+
+	if runes := []rune(text); len(runes) > maxSummaryLength {
+		return string(runes[:maxSummaryLength-3]) + "..."
+	}
+	return text
+}
@@ -0,0 +1,169 @@
+package search
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// defaultManDirs are searched when no MANPATH is set and manpath(1) is unavailable
+var defaultManDirs = []string{"/usr/share/man", "/usr/local/share/man", "/opt/homebrew/share/man"}
+
+// compressionExts are suffixes stripped from page filenames before the
+// section is read, e.g. "ls.1.gz" -> "ls.1"
+var compressionExts = []string{".gz", ".bz2", ".xz", ".lzma", ".zst", ".Z"}
+
+// indexCachePath returns where the name index is cached; tests replace it
+var indexCachePath = func() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "mantee", "index.json"), nil
+}
+
+// nameIndex is the cached list of pages found by scanning MANPATH
+type nameIndex struct {
+	ManPath string    `json:"manpath"` // MANPATH the index was built from
+	BuiltAt time.Time `json:"built_at"`
+	Pages   []ManPage `json:"pages"`
+}
+
+// searchIndex matches searchTerm against page names in the name index, for
+// use without man -k. Keyword mode matches name prefixes case-insensitively;
+// regex and wildcard modes match the whole name as usual. Pages from the
+// index have no description.
+func searchIndex(searchTerm string, opts SearchOptions) ([]ManPage, error) {
+	index := loadIndex(resolveManPath(opts))
+
+	if opts.Mode != MatchKeyword {
+		return filterPages(index.Pages, opts.Mode, searchTerm)
+	}
+	lowerTerm := strings.ToLower(searchTerm)
+	var results []ManPage
+	for _, page := range index.Pages {
+		if strings.HasPrefix(strings.ToLower(page.Name), lowerTerm) {
+			results = append(results, page)
+		}
+	}
+	return results, nil
+}
+
+// resolveManPath returns the MANPATH to index: the override from opts, the
+// environment, manpath(1), or the default directories, in that order
+func resolveManPath(opts SearchOptions) string {
+	if opts.Env.ManPath != "" {
+		return opts.Env.ManPath
+	}
+	if env := os.Getenv("MANPATH"); env != "" {
+		return env
+	}
+	if out, err := execCommand("manpath").Output(); err == nil {
+		if path := strings.TrimSpace(string(out)); path != "" {
+			return path
+		}
+	}
+	return strings.Join(defaultManDirs, ":")
+}
+
+// loadIndex returns the cached index for manPath, rebuilding it when missing,
+// built for another MANPATH, or older than any of its directories. Failure to
+// read or write the cache only costs a rescan.
+func loadIndex(manPath string) nameIndex {
+	cachePath, cacheErr := indexCachePath()
+	if cacheErr == nil {
+		if data, err := os.ReadFile(cachePath); err == nil {
+			var index nameIndex
+			if json.Unmarshal(data, &index) == nil && index.ManPath == manPath && !indexStale(index) {
+				return index
+			}
+		}
+	}
+
+	index := nameIndex{ManPath: manPath, BuiltAt: time.Now(), Pages: scanManPath(manPath)}
+	if cacheErr == nil {
+		if data, err := json.Marshal(index); err == nil {
+			if os.MkdirAll(filepath.Dir(cachePath), 0o755) == nil {
+				_ = os.WriteFile(cachePath, data, 0o644)
+			}
+		}
+	}
+	return index
+}
+
+// indexStale reports whether any MANPATH directory or section directory was
+// modified after the index was built (pages added or removed)
+func indexStale(index nameIndex) bool {
+	for _, root := range filepath.SplitList(index.ManPath) {
+		if root == "" {
+			continue
+		}
+		dirs, _ := filepath.Glob(filepath.Join(root, "man*"))
+		for _, dir := range append(dirs, root) {
+			if info, err := os.Stat(dir); err == nil && info.ModTime().After(index.BuiltAt) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// scanManPath lists the pages in every man<section> directory under the
+// MANPATH roots. Earlier roots win when a page appears more than once, as
+// they do for man itself.
+func scanManPath(manPath string) []ManPage {
+	var pages []ManPage
+	seen := make(map[string]bool)
+	for _, root := range filepath.SplitList(manPath) {
+		if root == "" {
+			continue
+		}
+		dirs, _ := filepath.Glob(filepath.Join(root, "man*"))
+		for _, dir := range dirs {
+			dirSection := strings.TrimPrefix(filepath.Base(dir), "man")
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				continue
+			}
+			for _, entry := range entries {
+				if entry.IsDir() {
+					continue
+				}
+				page, ok := pageFromFilename(entry.Name(), dirSection)
+				if !ok {
+					continue
+				}
+				key := page.Name + "(" + page.Section + ")"
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				pages = append(pages, page)
+			}
+		}
+	}
+	return pages
+}
+
+// pageFromFilename extracts the name and section from a page file such as
+// "printf.3p.gz" found in the man<dirSection> directory. Files whose
+// section does not belong to the directory are rejected.
+func pageFromFilename(filename, dirSection string) (ManPage, bool) {
+	for _, ext := range compressionExts {
+		if trimmed, ok := strings.CutSuffix(filename, ext); ok {
+			filename = trimmed
+			break
+		}
+	}
+	dot := strings.LastIndex(filename, ".")
+	if dot <= 0 || dot == len(filename)-1 {
+		return ManPage{}, false
+	}
+	name, section := filename[:dot], filename[dot+1:]
+	if dirSection == "" || !strings.HasPrefix(section, dirSection[:1]) {
+		return ManPage{}, false
+	}
+	return ManPage{Name: name, Section: section}, true
+}
@@ -0,0 +1,135 @@
+package search
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/shadyabhi/mantee/man"
+)
+
+// fakeManPath creates a MANPATH root holding the given files (relative
+// paths such as "man1/ls.1.gz") and points the index cache at a temp file
+func fakeManPath(t *testing.T, files ...string) string {
+	t.Helper()
+	root := t.TempDir()
+	for _, f := range files {
+		path := filepath.Join(root, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cache := filepath.Join(t.TempDir(), "index.json")
+	orig := indexCachePath
+	indexCachePath = func() (string, error) { return cache, nil }
+	t.Cleanup(func() { indexCachePath = orig })
+	return root
+}
+
+func TestPageFromFilename(t *testing.T) {
+	tests := []struct {
+		filename   string
+		dirSection string
+		want       ManPage
+		wantOK     bool
+	}{
+		{"ls.1.gz", "1", ManPage{Name: "ls", Section: "1"}, true},
+		{"printf.3p.gz", "3p", ManPage{Name: "printf", Section: "3p"}, true},
+		{"openssl-req.1ssl", "1", ManPage{Name: "openssl-req", Section: "1ssl"}, true},
+		{"git-ls-files.1", "1", ManPage{Name: "git-ls-files", Section: "1"}, true},
+		{"python3.11.1.bz2", "1", ManPage{Name: "python3.11", Section: "1"}, true},
+		{"ls.1.gz", "8", ManPage{}, false},
+		{"README", "1", ManPage{}, false},
+		{".1", "1", ManPage{}, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := pageFromFilename(tt.filename, tt.dirSection)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("pageFromFilename(%q, %q) = %v, %v, want %v, %v", tt.filename, tt.dirSection, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestScanManPath(t *testing.T) {
+	first := fakeManPath(t, "man1/ls.1.gz", "man3/printf.3.gz", "cat1/ls.0")
+	second := fakeManPath(t, "man1/ls.1", "man8/mount.8")
+
+	got := scanManPath(first + ":" + second)
+	want := []ManPage{
+		{Name: "ls", Section: "1"},
+		{Name: "printf", Section: "3"},
+		{Name: "mount", Section: "8"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("scanManPath() = %v, want %v", got, want)
+	}
+}
+
+func TestSearchManPagesIndex(t *testing.T) {
+	root := fakeManPath(t, "man1/git.1.gz", "man1/git-log.1.gz", "man1/ls.1.gz", "man3/gitfoo.3")
+	calls := fakeExec(t, "", "", 0)
+
+	pages, _, err := SearchManPages("1 git", SearchOptions{Index: true, Env: man.Env{ManPath: root}})
+	if err != nil {
+		t.Fatalf("SearchManPages() error = %v", err)
+	}
+	want := []ManPage{{Name: "git", Section: "1"}, {Name: "git-log", Section: "1"}}
+	if !reflect.DeepEqual(pages, want) {
+		t.Errorf("SearchManPages() = %v, want %v", pages, want)
+	}
+	if len(*calls) != 0 {
+		t.Errorf("index search ran commands: %v", *calls)
+	}
+}
+
+func TestSearchManPagesIndexFallback(t *testing.T) {
+	root := fakeManPath(t, "man1/ls.1.gz")
+	fakeExec(t, "", "apropos: cannot open the manual page database\n", 16)
+
+	pages, _, err := SearchManPages("ls", SearchOptions{Env: man.Env{ManPath: root}})
+	if err != nil {
+		t.Fatalf("SearchManPages() error = %v", err)
+	}
+	want := []ManPage{{Name: "ls", Section: "1"}}
+	if !reflect.DeepEqual(pages, want) {
+		t.Errorf("SearchManPages() = %v, want %v", pages, want)
+	}
+}
+
+func TestLoadIndexCache(t *testing.T) {
+	root := fakeManPath(t, "man1/ls.1.gz")
+
+	if got := loadIndex(root).Pages; len(got) != 1 {
+		t.Fatalf("loadIndex() = %v, want 1 page", got)
+	}
+
+	// A new page changes the directory's mtime, so the cache is rebuilt
+	if err := os.WriteFile(filepath.Join(root, "man1", "cat.1.gz"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(filepath.Join(root, "man1"), future, future); err != nil {
+		t.Fatal(err)
+	}
+	if got := loadIndex(root).Pages; len(got) != 2 {
+		t.Errorf("loadIndex() after adding a page = %v, want 2 pages", got)
+	}
+
+	// An unchanged tree is served from the cache
+	if err := os.Remove(filepath.Join(root, "man1", "cat.1.gz")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(filepath.Join(root, "man1"), time.Now().Add(-time.Hour), time.Now().Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if got := loadIndex(root).Pages; len(got) != 2 {
+		t.Errorf("loadIndex() from cache = %v, want 2 pages", got)
+	}
+}
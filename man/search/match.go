@@ -0,0 +1,261 @@
+package search
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/shadyabhi/mantee/man"
+)
+
+// MatchMode selects how the search keyword is interpreted
+type MatchMode int
+
+const (
+	MatchKeyword  MatchMode = iota // Plain apropos keyword (default)
+	MatchRegex                     // Keyword is a regular expression
+	MatchWildcard                  // Keyword is a shell-style wildcard
+)
+
+// String returns the mode name as used in flags and titles
+func (m MatchMode) String() string {
+	switch m {
+	case MatchRegex:
+		return "regex"
+	case MatchWildcard:
+		return "wildcard"
+	default:
+		return "keyword"
+	}
+}
+
+// flag returns the man -k flag enabling this mode natively
+func (m MatchMode) flag() string {
+	switch m {
+	case MatchRegex:
+		return "--regex"
+	case MatchWildcard:
+		return "--wildcard"
+	default:
+		return ""
+	}
+}
+
+// SortMode selects how search results are ordered
+type SortMode int
+
+const (
+	SortRelevance SortMode = iota // Names starting with the keyword first, then by name (default)
+	SortSection                   // Grouped by section (1, 2, 3, 3p, ...), then by name
+)
+
+// sortModeNames maps flag/config values to sort modes
+var sortModeNames = map[string]SortMode{
+	"relevance": SortRelevance,
+	"section":   SortSection,
+}
+
+// ParseSortMode returns the sort mode named by name ("relevance" or
+// "section"). Empty means the default.
+func ParseSortMode(name string) (SortMode, error) {
+	if name == "" {
+		return SortRelevance, nil
+	}
+	mode, ok := sortModeNames[name]
+	if !ok {
+		return SortRelevance, fmt.Errorf("unknown sort mode %q (want relevance or section)", name)
+	}
+	return mode, nil
+}
+
+// DefaultMaxResults is the result cap used when none is configured
+const DefaultMaxResults = 500
+
+// SearchOptions controls how SearchManPages queries man -k
+type SearchOptions struct {
+	Mode       MatchMode
+	Env        man.Env  // Environment overrides (MANPATH, LANG) for man
+	Index      bool     // Match page names from a MANPATH scan instead of man -k
+	Sort       SortMode // How results are ordered
+	MaxResults int      // Results kept after sorting (0 keeps all)
+	NamesOnly  bool     // Keep only pages whose name matches, not just the description
+
+	// KeepDuplicates lists every man -k entry, even a name(section) seen
+	// before, e.g. to debug a page missing from the results
+	KeepDuplicates bool
+}
+
+// Describe returns a short human-readable note of the match mode in effect,
+// or an empty string for plain keyword search
+func (o SearchOptions) Describe() string {
+	var notes []string
+	switch {
+	case o.Index:
+		if o.Mode != MatchKeyword {
+			notes = append(notes, o.Mode.String())
+		}
+		notes = append(notes, "name index")
+	case o.Mode == MatchKeyword:
+	case NativeMatchSupported(o.Mode):
+		notes = append(notes, o.Mode.String())
+	default:
+		notes = append(notes, o.Mode.String()+", filtered locally")
+	}
+	if o.NamesOnly {
+		notes = append(notes, "names only")
+	}
+	return strings.Join(notes, ", ")
+}
+
+// Matcher finds the text a search keyword matched, for highlighting
+// results. The zero value matches nothing.
+type Matcher struct {
+	re *regexp.Regexp
+}
+
+// Matcher compiles keyword into a Matcher, once per query rather than per
+// highlighted row. A leading section ("3 printf") is ignored. Keywords
+// match case-insensitively as literal text and regexes wherever they match;
+// wildcards match whole words or names, so nothing is highlighted for them.
+func (o SearchOptions) Matcher(keyword string) Matcher {
+	_, term := parseSectionPrefix(keyword)
+	if term == "" {
+		return Matcher{}
+	}
+
+	var pattern string
+	switch o.Mode {
+	case MatchRegex:
+		pattern = "(?i)" + term
+	case MatchWildcard:
+		return Matcher{}
+	default:
+		pattern = "(?i)" + regexp.QuoteMeta(term)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return Matcher{}
+	}
+	return Matcher{re: re}
+}
+
+// Ranges returns the byte ranges of text matched
+func (m Matcher) Ranges(text string) [][2]int {
+	if m.re == nil {
+		return nil
+	}
+	var ranges [][2]int
+	for _, loc := range m.re.FindAllStringIndex(text, -1) {
+		if loc[0] < loc[1] {
+			ranges = append(ranges, [2]int{loc[0], loc[1]})
+		}
+	}
+	return ranges
+}
+
+var (
+	nativeSupportMu    sync.Mutex
+	nativeSupportCache = map[MatchMode]bool{}
+)
+
+// NativeMatchSupported reports whether the system's man -k accepts the flag
+// for the given mode. The result is probed once and cached.
+func NativeMatchSupported(mode MatchMode) bool {
+	if mode == MatchKeyword {
+		return true
+	}
+
+	nativeSupportMu.Lock()
+	defer nativeSupportMu.Unlock()
+	if supported, ok := nativeSupportCache[mode]; ok {
+		return supported
+	}
+
+	cmd := execCommand("man", "-k", mode.flag(), "mantee-probe")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+
+	// An unknown flag produces a usage error; "nothing appropriate" means it worked
+	supported := err == nil || !isUsageError(stderr.String())
+	nativeSupportCache[mode] = supported
+	return supported
+}
+
+// isUsageError reports whether stderr from man looks like an option parsing failure
+func isUsageError(stderr string) bool {
+	stderr = strings.ToLower(stderr)
+	for _, marker := range []string{"unrecognized option", "illegal option", "invalid option", "unknown option", "usage:"} {
+		if strings.Contains(stderr, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterPages keeps pages whose name or description matches pattern under
+// the given mode. Used when man -k lacks native support for the mode.
+func filterPages(pages []ManPage, mode MatchMode, pattern string) ([]ManPage, error) {
+	var match func(string) bool
+	switch mode {
+	case MatchRegex:
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return nil, err
+		}
+		match = re.MatchString
+	case MatchWildcard:
+		lowerPattern := strings.ToLower(pattern)
+		if _, err := filepath.Match(lowerPattern, ""); err != nil {
+			return nil, err
+		}
+		match = func(s string) bool {
+			ok, _ := filepath.Match(lowerPattern, strings.ToLower(s))
+			return ok
+		}
+	default:
+		return pages, nil
+	}
+
+	filtered := make([]ManPage, 0, len(pages))
+	for _, page := range pages {
+		if match(page.Name) || match(page.Description) {
+			filtered = append(filtered, page)
+		}
+	}
+	return filtered, nil
+}
+
+// filterNames keeps the pages whose name matches pattern, ignoring what man -k
+// matched in descriptions: names starting with a keyword, or matching a
+// regex or wildcard. Matching is case-insensitive.
+func filterNames(pages []ManPage, mode MatchMode, pattern string) []ManPage {
+	lowerPattern := strings.ToLower(pattern)
+	match := func(name string) bool {
+		return strings.HasPrefix(strings.ToLower(name), lowerPattern)
+	}
+	switch mode {
+	case MatchRegex:
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return nil
+		}
+		match = re.MatchString
+	case MatchWildcard:
+		match = func(name string) bool {
+			ok, _ := filepath.Match(lowerPattern, strings.ToLower(name))
+			return ok
+		}
+	}
+
+	filtered := make([]ManPage, 0, len(pages))
+	for _, page := range pages {
+		if match(page.Name) {
+			filtered = append(filtered, page)
+		}
+	}
+	return filtered
+}
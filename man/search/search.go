@@ -3,13 +3,22 @@ package search
 import (
 	"bufio"
 	"bytes"
+	"errors"
+	"fmt"
+	"log"
 	"os/exec"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 	"unicode"
 )
 
+// execCommand is the constructor used for external commands; tests replace it
+// to inject canned output
+var execCommand = exec.Command
+
 // ManPage represents a single man page entry from search results
 type ManPage struct {
 	Name        string
@@ -17,11 +26,35 @@ type ManPage struct {
 	Description string
 }
 
-// String returns a formatted display string for the man page
+// String returns a formatted display string for the man page. The section
+// is omitted when unknown.
 func (m ManPage) String() string {
+	if m.Section == "" {
+		return m.Name + " - " + m.Description
+	}
 	return m.Name + "(" + m.Section + ") - " + m.Description
 }
 
+// FormatResults lists pages one per line as "name(section) - description",
+// the text --list prints and the result list copies
+func FormatResults(pages []ManPage) string {
+	var b strings.Builder
+	for _, page := range pages {
+		b.WriteString(page.String())
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Command returns the man invocation that opens this page, e.g. "man 1 curl".
+// The section is omitted when unknown.
+func (m ManPage) Command() string {
+	if m.Section == "" {
+		return "man " + m.Name
+	}
+	return "man " + m.Section + " " + m.Name
+}
+
 // parseSectionPrefix checks if the keyword starts with a section number.
 // If the keyword starts with a number followed by a space (e.g., "1 curl"),
 // it returns the section and the remaining keyword.
@@ -57,23 +90,108 @@ func parseSectionPrefix(keyword string) (section, searchTerm string) {
 
 // SearchManPages executes 'man -k <keyword>' and parses the results.
 // If keyword starts with a section number (e.g., "1 curl"), searches only that section.
-func SearchManPages(keyword string) ([]ManPage, error) {
+// For regex/wildcard modes the flag is passed to man -k when supported; otherwise
+// all pages are listed and filtered locally. With opts.Index, or when man -k
+// itself is unavailable, page names are matched against an index built by
+// scanning MANPATH instead. With opts.NamesOnly, pages matched only by their
+// description are dropped. Sorted results are capped at opts.MaxResults;
+// total is the number found before the cap.
+func SearchManPages(keyword string, opts SearchOptions) (pages []ManPage, total int, err error) {
+	start := time.Now()
 	section, searchTerm := parseSectionPrefix(keyword)
 
+	var results []ManPage
+	if opts.Index {
+		results, err = searchIndex(searchTerm, opts)
+	} else {
+		results, err = searchApropos(searchTerm, opts)
+		if errors.Is(err, errAproposUnavailable) {
+			// The index scans local directories, which says nothing about
+			// the pages on a remote host
+			if len(opts.Env.Exec) > 0 {
+				return nil, 0, fmt.Errorf("%w via %s", err, strings.Join(opts.Env.Exec, " "))
+			}
+			results, err = searchIndex(searchTerm, opts)
+		}
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid %s pattern: %w", opts.Mode, err)
+	}
+
+	// Filter by section if specified
+	if section != "" {
+		filtered := make([]ManPage, 0, len(results))
+		for _, page := range results {
+			if page.Section == section {
+				filtered = append(filtered, page)
+			}
+		}
+		results = filtered
+	}
+	if opts.NamesOnly {
+		results = filterNames(results, opts.Mode, searchTerm)
+	}
+
+	sortManPages(results, searchTerm, opts.Sort)
+	total = len(results)
+	log.Printf("search %q (%s): %d results in %v", keyword, opts.Mode, total, time.Since(start))
+	if opts.MaxResults > 0 && total > opts.MaxResults {
+		results = results[:opts.MaxResults]
+	}
+	return results, total, nil
+}
+
+// CheckApropos runs a sample man -k, as --doctor does, and returns why
+// keyword search can't use it: man -k failing outright (e.g. no whatis
+// database) or finding nothing for "man" (an empty database)
+func CheckApropos(opts SearchOptions) error {
+	opts.Mode = MatchKeyword
+	pages, err := searchApropos("man", opts)
+	if err != nil {
+		return err
+	}
+	if len(pages) == 0 {
+		return fmt.Errorf("man -k found nothing for %q", "man")
+	}
+	return nil
+}
+
+// errAproposUnavailable means man -k could not be run or failed outright
+// (e.g. no mandb database), as opposed to finding nothing
+var errAproposUnavailable = errors.New("man -k unavailable")
+
+// searchApropos runs man -k for searchTerm. Errors other than
+// errAproposUnavailable come from an invalid regex/wildcard pattern.
+func searchApropos(searchTerm string, opts SearchOptions) ([]ManPage, error) {
 	// Always search without -S flag, then filter by section in code.
 	// macOS's man -S can miss exact matches like "ls" when searching "1 ls".
-	var cmd *exec.Cmd
-	if section != "" {
-		cmd = exec.Command("man", "-k", searchTerm)
-	} else {
-		cmd = exec.Command("man", "-k", keyword)
+	args := []string{"-k"}
+	localFilter := false
+	switch {
+	case opts.Mode == MatchKeyword:
+		args = append(args, searchTerm)
+	case len(opts.Env.Exec) == 0 && NativeMatchSupported(opts.Mode):
+		// The probe runs the local man, so a remote one is always filtered here
+		args = append(args, opts.Mode.flag(), searchTerm)
+	default:
+		// List everything and filter with Go's regexp/filepath.Match below
+		args = append(args, ".")
+		localFilter = true
 	}
+	argv := opts.Env.Command(nil, args...)
+	cmd := execCommand(argv[0], argv[1:]...)
+	opts.Env.Apply(cmd)
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
 	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		// man itself could not be started
+		return nil, errAproposUnavailable
+	}
 	if err != nil {
 		// man -k returns exit code 1 when no results found
 		if stderr.Len() > 0 && strings.Contains(stderr.String(), "nothing appropriate") {
@@ -83,40 +201,34 @@ func SearchManPages(keyword string) ([]ManPage, error) {
 		if strings.Contains(stdout.String(), "nothing appropriate") {
 			return []ManPage{}, nil
 		}
+		// A complaint on stderr with no output means the search itself
+		// failed, e.g. apropos has no database to read
+		if stdout.Len() == 0 && stderr.Len() > 0 {
+			return nil, errAproposUnavailable
+		}
 		// Some systems return exit 1 but still have valid output
 		if stdout.Len() == 0 {
 			return []ManPage{}, nil
 		}
 	}
 
-	results := parseManOutput(stdout.String())
-
-	// Filter by section if specified
-	if section != "" {
-		filtered := make([]ManPage, 0, len(results))
-		for _, page := range results {
-			if page.Section == section {
-				filtered = append(filtered, page)
-			}
-		}
-		results = filtered
+	results := parseManOutput(stdout.String(), opts.KeepDuplicates)
+	if localFilter {
+		return filterPages(results, opts.Mode, searchTerm)
 	}
-
-	sortManPages(results, searchTerm)
 	return results, nil
 }
 
 // parseManOutput parses the output of 'man -k' into ManPage structs
 // Format: name(section) - description
 // Or: name, name2(section) - description (multiple names)
-func parseManOutput(output string) []ManPage {
+// Some systems and locales separate the description with a tab (or a dash
+// variant) instead of " - ", or omit the section; such pages get an empty
+// Section, which man resolves itself. Unless keepDuplicates, a name(section)
+// listed more than once is kept once, with the longest description seen.
+func parseManOutput(output string, keepDuplicates bool) []ManPage {
 	var results []ManPage
-	seen := make(map[string]bool)
-
-	// Regex to match: name(section) - description
-	// Also handles: name(sec), name2(sec) - description (multiple names with sections)
-	// Uses .* to greedily match everything up to the last (section) before " - "
-	re := regexp.MustCompile(`^(.*)\(([^)]+)\)\s+-\s+(.*)$`)
+	seen := make(map[string]int) // Index in results by name(section)
 
 	scanner := bufio.NewScanner(strings.NewReader(output))
 	for scanner.Scan() {
@@ -125,53 +237,81 @@ func parseManOutput(output string) []ManPage {
 			continue
 		}
 
-		matches := re.FindStringSubmatch(line)
-		if matches != nil {
-			namesStr := strings.TrimSpace(matches[1])
-			lastSection := strings.TrimSpace(matches[2])
-			description := strings.TrimSpace(matches[3])
-
-			// Parse all names from formats like:
-			// "opendir(3), readdir(3), closedir(3)" or "grep, egrep, fgrep"
-			// Each name might have its own (section) or share the last one
-			nameRe := regexp.MustCompile(`([a-zA-Z0-9_.-]+)(?:\(([^)]+)\))?`)
-			nameMatches := nameRe.FindAllStringSubmatch(namesStr, -1)
-
-			for _, nm := range nameMatches {
-				name := strings.TrimSpace(nm[1])
-				section := lastSection
-				if nm[2] != "" {
-					section = strings.TrimSpace(nm[2])
-				}
+		loc := descSeparatorRe.FindStringIndex(line)
+		if loc == nil {
+			continue
+		}
+		namesStr := strings.TrimSpace(line[:loc[0]])
+		description := strings.TrimSpace(line[loc[1]:])
 
-				// Deduplicate by name+section
-				key := name + "(" + section + ")"
-				if seen[key] {
-					continue
-				}
-				seen[key] = true
+		// The section after the last name applies to names without their own
+		var lastSection string
+		if m := trailingSectionRe.FindStringSubmatchIndex(namesStr); m != nil {
+			lastSection = strings.TrimSpace(namesStr[m[2]:m[3]])
+			namesStr = namesStr[:m[0]]
+		}
 
-				results = append(results, ManPage{
-					Name:        name,
-					Section:     section,
-					Description: description,
-				})
+		// Parse all names from formats like:
+		// "opendir(3), readdir(3), closedir(3)" or "grep, egrep, fgrep"
+		// Each name might have its own (section) or share the last one
+		for _, nm := range nameRe.FindAllStringSubmatch(namesStr, -1) {
+			name := strings.TrimSpace(nm[1])
+			section := lastSection
+			if nm[2] != "" {
+				section = strings.TrimSpace(nm[2])
 			}
+
+			// Deduplicate by name+section, keeping the richer description
+			key := name + "(" + section + ")"
+			if i, ok := seen[key]; ok && !keepDuplicates {
+				if len(description) > len(results[i].Description) {
+					results[i].Description = description
+				}
+				continue
+			}
+			seen[key] = len(results)
+
+			results = append(results, ManPage{
+				Name:        name,
+				Section:     section,
+				Description: description,
+			})
 		}
 	}
 
 	return results
 }
 
+var (
+	// descSeparatorRe matches the first separator between the names and the
+	// description: " - " (or an en/em dash), or a run of tabs
+	descSeparatorRe = regexp.MustCompile(`\s+[-–—]\s+|\t+[-–—]?\s*`)
+
+	// trailingSectionRe matches the "(section)" ending the names, e.g. "ls (1)"
+	trailingSectionRe = regexp.MustCompile(`\s*\(([^)]+)\)$`)
+
+	// nameRe matches one page name, optionally with its own section
+	nameRe = regexp.MustCompile(`([a-zA-Z0-9_.:+-]+)(?:\(([^)]+)\))?`)
+)
+
 // sortManPages sorts man pages so that:
 // 1. Exact prefix matches (names starting with keyword) come first
 // 2. Within each group, results are sorted alphabetically by name
-func sortManPages(pages []ManPage, keyword string) {
+// With SortSection, pages are instead grouped by section (see compareSections)
+// and sorted by name within each.
+func sortManPages(pages []ManPage, keyword string, mode SortMode) {
 	keywordLower := strings.ToLower(keyword)
 	sort.Slice(pages, func(i, j int) bool {
 		nameI := strings.ToLower(pages[i].Name)
 		nameJ := strings.ToLower(pages[j].Name)
 
+		if mode == SortSection {
+			if c := compareSections(pages[i].Section, pages[j].Section); c != 0 {
+				return c < 0
+			}
+			return nameI < nameJ
+		}
+
 		prefixI := strings.HasPrefix(nameI, keywordLower)
 		prefixJ := strings.HasPrefix(nameJ, keywordLower)
 
@@ -187,3 +327,34 @@ func sortManPages(pages []ManPage, keyword string) {
 		return nameI < nameJ
 	})
 }
+
+// compareSections orders sections by their leading number, then by suffix,
+// so "1" < "3" < "3p" < "3ssl" < "8". Sections without a number ("n", "l")
+// come last, alphabetically.
+func compareSections(a, b string) int {
+	numA, restA := splitSection(a)
+	numB, restB := splitSection(b)
+	switch {
+	case numA == -1 && numB != -1:
+		return 1
+	case numA != -1 && numB == -1:
+		return -1
+	case numA != numB:
+		return numA - numB
+	}
+	return strings.Compare(restA, restB)
+}
+
+// splitSection splits a section like "3p" into its number (3) and suffix
+// ("p"). The number is -1 if the section does not start with a digit.
+func splitSection(section string) (int, string) {
+	end := strings.IndexFunc(section, func(r rune) bool { return !unicode.IsDigit(r) })
+	if end == -1 {
+		end = len(section)
+	}
+	num, err := strconv.Atoi(section[:end])
+	if err != nil {
+		return -1, section
+	}
+	return num, section[end:]
+}
@@ -0,0 +1,460 @@
+package search
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/shadyabhi/mantee/internal/mantest"
+	"github.com/shadyabhi/mantee/man"
+)
+
+// fakeExec fakes this package's execCommand (see mantest.FakeExec)
+func fakeExec(t *testing.T, stdout, stderr string, exitCode int) *[][]string {
+	t.Helper()
+	return mantest.FakeExec(t, &execCommand, stdout, stderr, exitCode)
+}
+
+// TestHelperProcess is not a real test; it is the fake external command
+// spawned by fakeExec
+func TestHelperProcess(t *testing.T) {
+	mantest.HelperProcess()
+}
+
+func TestManPageCommand(t *testing.T) {
+	tests := []struct {
+		page ManPage
+		want string
+	}{
+		{ManPage{Name: "curl", Section: "1"}, "man 1 curl"},
+		{ManPage{Name: "printf", Section: "3p"}, "man 3p printf"},
+		{ManPage{Name: "intro"}, "man intro"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.page.Command(); got != tt.want {
+			t.Errorf("Command() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestFormatResults(t *testing.T) {
+	pages := []ManPage{
+		{Name: "tcp", Section: "7", Description: "TCP protocol"},
+		{Name: "intro", Description: "introduction"},
+	}
+	want := "tcp(7) - TCP protocol\nintro - introduction\n"
+	if got := FormatResults(pages); got != want {
+		t.Errorf("FormatResults() = %q, want %q", got, want)
+	}
+	if got := FormatResults(nil); got != "" {
+		t.Errorf("FormatResults(nil) = %q, want empty", got)
+	}
+}
+
+func TestParseSectionPrefix(t *testing.T) {
+	tests := []struct {
+		keyword     string
+		wantSection string
+		wantTerm    string
+	}{
+		{"curl", "", "curl"},
+		{"1 curl", "1", "curl"},
+		{"3p printf", "3p", "printf"},
+		{"  8   mount  ", "8", "mount"},
+		{"1", "", "1"},
+		{"1 ", "", "1"},
+		{"git log", "", "git log"},
+		{"", "", ""},
+	}
+
+	for _, tt := range tests {
+		section, term := parseSectionPrefix(tt.keyword)
+		if section != tt.wantSection || term != tt.wantTerm {
+			t.Errorf("parseSectionPrefix(%q) = (%q, %q), want (%q, %q)",
+				tt.keyword, section, term, tt.wantSection, tt.wantTerm)
+		}
+	}
+}
+
+func TestParseManOutput(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    []ManPage
+	}{
+		{
+			fixture: "man-k-linux.txt",
+			want: []ManPage{
+				{Name: "ls", Section: "1", Description: "list directory contents"},
+				{Name: "lsattr", Section: "1", Description: "list file attributes on a Linux second extended file system"},
+				{Name: "lsblk", Section: "8", Description: "list block devices"},
+				{Name: "dircolors", Section: "1", Description: "color setup for ls"},
+				{Name: "lsearch", Section: "3", Description: "linear search of an array"},
+				{Name: "ls", Section: "1p", Description: "list directory contents"},
+			},
+		},
+		{
+			fixture: "man-k-macos.txt",
+			want: []ManPage{
+				{Name: "ls", Section: "1", Description: "list directory contents"},
+				{Name: "grep", Section: "1", Description: "file pattern searcher"},
+				{Name: "egrep", Section: "1", Description: "file pattern searcher"},
+				{Name: "fgrep", Section: "1", Description: "file pattern searcher"},
+				{Name: "rgrep", Section: "1", Description: "file pattern searcher"},
+				{Name: "opendir", Section: "3", Description: "directory operations"},
+				{Name: "readdir", Section: "3", Description: "directory operations"},
+				{Name: "closedir", Section: "3", Description: "directory operations"},
+				{Name: "git-ls-files", Section: "1", Description: "Show information about files in the index and the working tree"},
+			},
+		},
+		{
+			fixture: "man-k-freebsd.txt",
+			want: []ManPage{
+				{Name: "ls", Section: "1", Description: "list directory contents"},
+				{Name: "lsextattr", Section: "8", Description: "manipulate extended attributes"},
+				{Name: "rmextattr", Section: "8", Description: "manipulate extended attributes"},
+				{Name: "lsvfs", Section: "1", Description: "list installed virtual file systems"},
+				{Name: "lockf", Section: "1", Description: "execute a command while holding a file lock"},
+			},
+		},
+		{
+			fixture: "man-k-locale.txt",
+			want: []ManPage{
+				{Name: "ls", Section: "1", Description: "Verzeichnisinhalte auflisten"},
+				{Name: "dir", Section: "1", Description: "Verzeichnisinhalte auflisten"},
+				{Name: "lsblk", Section: "", Description: "Blockgeräte auflisten"},
+				{Name: "ls-files", Section: "", Description: "Informationen über Dateien im Index anzeigen"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			got := parseManOutput(mantest.ReadFixture(t, tt.fixture), false)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseManOutput() =\n%#v\nwant\n%#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseManOutputDuplicates(t *testing.T) {
+	output := mantest.ReadFixture(t, "man-k-duplicates.txt")
+
+	got := parseManOutput(output, false)
+	want := []ManPage{
+		{Name: "printf", Section: "1", Description: "format and print data; also a shell builtin, see bash(1)"},
+		{Name: "printf", Section: "3", Description: "formatted output conversion"},
+		{Name: "bash", Section: "1", Description: "GNU Bourne-Again SHell"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseManOutput() =\n%#v\nwant\n%#v", got, want)
+	}
+
+	if got := parseManOutput(output, true); len(got) != 5 {
+		t.Errorf("parseManOutput(keepDuplicates) = %d pages, want all 5", len(got))
+	}
+}
+
+func TestSortManPagesBySection(t *testing.T) {
+	pages := []ManPage{
+		{Name: "printf", Section: "3p"},
+		{Name: "mount", Section: "8"},
+		{Name: "printf", Section: "1"},
+		{Name: "tcl", Section: "n"},
+		{Name: "printf", Section: "3"},
+		{Name: "ls", Section: "1"},
+		{Name: "ssl", Section: "7ssl"},
+		{Name: "intro", Section: "10"},
+	}
+	sortManPages(pages, "printf", SortSection)
+
+	var got []string
+	for _, p := range pages {
+		got = append(got, p.Name+"("+p.Section+")")
+	}
+	want := []string{"ls(1)", "printf(1)", "printf(3)", "printf(3p)", "ssl(7ssl)", "mount(8)", "intro(10)", "tcl(n)"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sortManPages() = %v, want %v", got, want)
+	}
+}
+
+func TestParseSortMode(t *testing.T) {
+	for name, want := range map[string]SortMode{"": SortRelevance, "relevance": SortRelevance, "section": SortSection} {
+		if got, err := ParseSortMode(name); err != nil || got != want {
+			t.Errorf("ParseSortMode(%q) = %v, %v, want %v", name, got, err, want)
+		}
+	}
+	if _, err := ParseSortMode("name"); err == nil {
+		t.Error("ParseSortMode() expected error for unknown mode")
+	}
+}
+
+func TestSortManPages(t *testing.T) {
+	pages := []ManPage{
+		{Name: "dircolors"},
+		{Name: "lsblk"},
+		{Name: "Ls"},
+		{Name: "alias"},
+	}
+	sortManPages(pages, "ls", SortRelevance)
+
+	var names []string
+	for _, p := range pages {
+		names = append(names, p.Name)
+	}
+	want := []string{"Ls", "lsblk", "alias", "dircolors"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("sortManPages() = %v, want %v", names, want)
+	}
+}
+
+func TestSearchManPages(t *testing.T) {
+	calls := fakeExec(t, mantest.ReadFixture(t, "man-k-linux.txt"), "", 0)
+
+	pages, _, err := SearchManPages("ls", SearchOptions{})
+	if err != nil {
+		t.Fatalf("SearchManPages() error = %v", err)
+	}
+	if got := (*calls)[0]; !reflect.DeepEqual(got, []string{"man", "-k", "ls"}) {
+		t.Errorf("unexpected command: %v", got)
+	}
+	if len(pages) != 6 {
+		t.Fatalf("got %d pages, want 6", len(pages))
+	}
+	if pages[0].Name != "ls" {
+		t.Errorf("first result = %q, want prefix match %q", pages[0].Name, "ls")
+	}
+}
+
+func TestSearchManPagesMaxResults(t *testing.T) {
+	fakeExec(t, mantest.ReadFixture(t, "man-k-linux.txt"), "", 0)
+
+	pages, total, err := SearchManPages("ls", SearchOptions{MaxResults: 2})
+	if err != nil {
+		t.Fatalf("SearchManPages() error = %v", err)
+	}
+	if len(pages) != 2 || total != 6 {
+		t.Fatalf("got %d pages of %d, want 2 of 6", len(pages), total)
+	}
+	// The cap applies after sorting, so the best matches are kept
+	if pages[0].Name != "ls" {
+		t.Errorf("first result = %q, want %q", pages[0].Name, "ls")
+	}
+}
+
+func TestSearchManPagesSectionFilter(t *testing.T) {
+	calls := fakeExec(t, mantest.ReadFixture(t, "man-k-linux.txt"), "", 0)
+
+	pages, _, err := SearchManPages("1 ls", SearchOptions{})
+	if err != nil {
+		t.Fatalf("SearchManPages() error = %v", err)
+	}
+	if got := (*calls)[0]; !reflect.DeepEqual(got, []string{"man", "-k", "ls"}) {
+		t.Errorf("section should be filtered in code, got command %v", got)
+	}
+	for _, p := range pages {
+		if p.Section != "1" {
+			t.Errorf("got page %s outside section 1", p)
+		}
+	}
+	if len(pages) != 3 {
+		t.Errorf("got %d pages, want 3", len(pages))
+	}
+}
+
+func TestSearchManPagesNamesOnly(t *testing.T) {
+	tests := []struct {
+		keyword string
+		mode    MatchMode
+		want    []string
+	}{
+		// dircolors is only matched by its description
+		{"ls", MatchKeyword, []string{"ls", "ls", "lsattr", "lsblk", "lsearch"}},
+		{"1 LS", MatchKeyword, []string{"ls", "lsattr"}},
+		{"^ls$", MatchRegex, []string{"ls", "ls"}},
+		{"ls?", MatchWildcard, nil},
+		{"ls*k", MatchWildcard, []string{"lsblk"}},
+	}
+
+	// The fake man -k lists every page, as if the flags were native
+	nativeSupportCache = map[MatchMode]bool{MatchRegex: true, MatchWildcard: true}
+	t.Cleanup(func() { nativeSupportCache = map[MatchMode]bool{} })
+
+	for _, tt := range tests {
+		fakeExec(t, mantest.ReadFixture(t, "man-k-linux.txt"), "", 0)
+
+		pages, _, err := SearchManPages(tt.keyword, SearchOptions{Mode: tt.mode, NamesOnly: true})
+		if err != nil {
+			t.Fatalf("SearchManPages(%q) error = %v", tt.keyword, err)
+		}
+		var names []string
+		for _, p := range pages {
+			names = append(names, p.Name)
+		}
+		if !reflect.DeepEqual(names, tt.want) {
+			t.Errorf("SearchManPages(%q, %s) names = %v, want %v", tt.keyword, tt.mode, names, tt.want)
+		}
+	}
+}
+
+func TestSearchManPagesNothingAppropriate(t *testing.T) {
+	fakeExec(t, "", "xyzzy: nothing appropriate.\n", 1)
+
+	pages, _, err := SearchManPages("xyzzy", SearchOptions{})
+	if err != nil {
+		t.Fatalf("SearchManPages() error = %v", err)
+	}
+	if len(pages) != 0 {
+		t.Errorf("got %d pages, want 0", len(pages))
+	}
+}
+
+func TestSearchManPagesEnv(t *testing.T) {
+	t.Setenv("HELPER_EXPAND", "1")
+	fakeExec(t, "ls (1) - $LANG\n", "", 0)
+
+	pages, _, err := SearchManPages("ls", SearchOptions{Env: man.Env{Lang: "de_DE.UTF-8"}})
+	if err != nil {
+		t.Fatalf("SearchManPages() error = %v", err)
+	}
+	want := []ManPage{{Name: "ls", Section: "1", Description: "de_DE.UTF-8"}}
+	if !reflect.DeepEqual(pages, want) {
+		t.Errorf("SearchManPages() = %v, want %v", pages, want)
+	}
+}
+
+func TestFilterPages(t *testing.T) {
+	pages := []ManPage{
+		{Name: "git-log", Description: "Show commit logs"},
+		{Name: "git-ls-files", Description: "Show information about files"},
+		{Name: "gitk", Description: "The Git repository browser"},
+		{Name: "ls", Description: "list directory contents"},
+	}
+
+	tests := []struct {
+		mode    MatchMode
+		pattern string
+		want    []string
+	}{
+		{MatchRegex, "^git-", []string{"git-log", "git-ls-files"}},
+		{MatchRegex, "BROWSER", []string{"gitk"}},
+		{MatchWildcard, "git-*", []string{"git-log", "git-ls-files"}},
+		{MatchWildcard, "l?", []string{"ls"}},
+		{MatchKeyword, "anything", []string{"git-log", "git-ls-files", "gitk", "ls"}},
+	}
+
+	for _, tt := range tests {
+		got, err := filterPages(pages, tt.mode, tt.pattern)
+		if err != nil {
+			t.Fatalf("filterPages(%s, %q) error = %v", tt.mode, tt.pattern, err)
+		}
+		var names []string
+		for _, p := range got {
+			names = append(names, p.Name)
+		}
+		if !reflect.DeepEqual(names, tt.want) {
+			t.Errorf("filterPages(%s, %q) = %v, want %v", tt.mode, tt.pattern, names, tt.want)
+		}
+	}
+
+	if _, err := filterPages(pages, MatchRegex, "("); err == nil {
+		t.Error("filterPages() expected error for invalid regex")
+	}
+}
+
+func TestSearchManPagesRegexNative(t *testing.T) {
+	nativeSupportCache = map[MatchMode]bool{}
+	t.Cleanup(func() { nativeSupportCache = map[MatchMode]bool{} })
+	calls := fakeExec(t, mantest.ReadFixture(t, "man-k-macos.txt"), "", 0)
+
+	if _, _, err := SearchManPages("^git-", SearchOptions{Mode: MatchRegex}); err != nil {
+		t.Fatalf("SearchManPages() error = %v", err)
+	}
+	// First call is the support probe, second the actual search
+	if got := (*calls)[1]; !reflect.DeepEqual(got, []string{"man", "-k", "--regex", "^git-"}) {
+		t.Errorf("unexpected command: %v", got)
+	}
+}
+
+func TestSearchManPagesRegexFallback(t *testing.T) {
+	nativeSupportCache = map[MatchMode]bool{MatchRegex: false}
+	t.Cleanup(func() { nativeSupportCache = map[MatchMode]bool{} })
+	calls := fakeExec(t, mantest.ReadFixture(t, "man-k-macos.txt"), "", 0)
+
+	pages, _, err := SearchManPages("^git-", SearchOptions{Mode: MatchRegex})
+	if err != nil {
+		t.Fatalf("SearchManPages() error = %v", err)
+	}
+	if got := (*calls)[0]; !reflect.DeepEqual(got, []string{"man", "-k", "."}) {
+		t.Errorf("unexpected command: %v", got)
+	}
+	if len(pages) != 1 || pages[0].Name != "git-ls-files" {
+		t.Errorf("SearchManPages() = %v, want only git-ls-files", pages)
+	}
+}
+
+func TestMatcherRanges(t *testing.T) {
+	tests := []struct {
+		mode    MatchMode
+		keyword string
+		text    string
+		want    [][2]int
+	}{
+		{MatchKeyword, "ls", "ls(1) - list directory contents", [][2]int{{0, 2}}},
+		{MatchKeyword, "LIST", "lsattr(1) - list file attributes, not a list", [][2]int{{12, 16}, {40, 44}}},
+		{MatchKeyword, "1 ls", "ls(1) - list directory contents", [][2]int{{0, 2}}},
+		{MatchKeyword, "a.b", "axb(1) - a.b", [][2]int{{9, 12}}},
+		{MatchKeyword, "grep", "ls(1) - list directory contents", nil},
+		{MatchRegex, "^ls[a-z]+", "lsblk(8) - list block devices", [][2]int{{0, 5}}},
+		{MatchRegex, "x*", "ls(1) - list", nil},
+		{MatchRegex, "(", "ls(1) - list", nil},
+		{MatchWildcard, "ls*", "ls(1) - list", nil},
+	}
+
+	for _, tt := range tests {
+		got := SearchOptions{Mode: tt.mode}.Matcher(tt.keyword).Ranges(tt.text)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Matcher(%v, %q, %q) = %v, want %v", tt.mode, tt.keyword, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestIsUsageError(t *testing.T) {
+	tests := []struct {
+		stderr string
+		want   bool
+	}{
+		{"man: unrecognized option '--regex'", true},
+		{"apropos: illegal option -- -\nusage: apropos [-afk] ...", true},
+		{"mantee-probe: nothing appropriate.", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := isUsageError(tt.stderr); got != tt.want {
+			t.Errorf("isUsageError(%q) = %v, want %v", tt.stderr, got, tt.want)
+		}
+	}
+}
+
+func TestCheckApropos(t *testing.T) {
+	fakeExec(t, "man (1) - an interface to the system reference manuals\n", "", 0)
+	if err := CheckApropos(SearchOptions{}); err != nil {
+		t.Errorf("CheckApropos() error = %v", err)
+	}
+}
+
+func TestCheckAproposEmpty(t *testing.T) {
+	fakeExec(t, "", "man: nothing appropriate.\n", 16)
+	if err := CheckApropos(SearchOptions{}); err == nil {
+		t.Error("CheckApropos() with no results should fail")
+	}
+}
+
+func TestCheckAproposUnavailable(t *testing.T) {
+	fakeExec(t, "", "apropos: cannot read database\n", 1)
+	if err := CheckApropos(SearchOptions{}); !errors.Is(err, errAproposUnavailable) {
+		t.Errorf("CheckApropos() error = %v, want errAproposUnavailable", err)
+	}
+}
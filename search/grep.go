@@ -0,0 +1,184 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/shadyabhi/mantee/man/search"
+)
+
+// GrepHit is a line of a man page containing the grepped term
+type GrepHit struct {
+	Page search.ManPage
+	Line int    // Index of the line in the page's content
+	Text string // The line, trimmed
+}
+
+// GrepModel lists the lines of several man pages that contain a term, for
+// picking one to open at that line
+type GrepModel struct {
+	term         string
+	keyword      string // Keyword the grepped pages were found with
+	searched     int    // Number of pages grepped
+	hits         []GrepHit
+	cursor       int
+	scrollOffset int
+	selected     *GrepHit
+	quitting     bool
+	width        int
+	height       int
+}
+
+// NewGrep creates a GrepModel for hits of term across searched pages found
+// with keyword
+func NewGrep(term, keyword string, searched int, hits []GrepHit) GrepModel {
+	return GrepModel{term: term, keyword: keyword, searched: searched, hits: hits}
+}
+
+// Init implements tea.Model
+func (m GrepModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model
+func (m GrepModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.adjustScroll()
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q", "esc":
+			m.quitting = true
+			return m, tea.Quit
+
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+				m.adjustScroll()
+			}
+
+		case "down", "j":
+			if m.cursor < len(m.hits)-1 {
+				m.cursor++
+				m.adjustScroll()
+			}
+
+		case "enter":
+			if len(m.hits) > 0 {
+				m.selected = &m.hits[m.cursor]
+			}
+			return m, tea.Quit
+
+		case "home", "g":
+			m.cursor = 0
+			m.scrollOffset = 0
+
+		case "end", "G":
+			m.cursor = max(len(m.hits)-1, 0)
+			m.adjustScroll()
+		}
+	}
+	return m, nil
+}
+
+// viewportHeight returns the number of hits that fit in the viewport
+func (m GrepModel) viewportHeight() int {
+	// Reserve lines for: title (2 lines with spacing), help line (2 lines with spacing)
+	reserved := 4
+	if m.height <= reserved {
+		return 10 // Minimum fallback
+	}
+	return m.height - reserved
+}
+
+// adjustScroll ensures the cursor is visible within the viewport
+func (m *GrepModel) adjustScroll() {
+	vpHeight := m.viewportHeight()
+	if m.cursor < m.scrollOffset {
+		m.scrollOffset = m.cursor
+	} else if m.cursor >= m.scrollOffset+vpHeight {
+		m.scrollOffset = m.cursor - vpHeight + 1
+	}
+}
+
+// View implements tea.Model
+func (m GrepModel) View() string {
+	if m.quitting || m.selected != nil {
+		return ""
+	}
+	if m.width > 0 && (m.width < minWidth || m.height < minHeight) {
+		return fmt.Sprintf("Terminal too small (need at least %dx%d, have %dx%d)", minWidth, minHeight, m.width, m.height)
+	}
+
+	s := titleStyle.Render(fmt.Sprintf("Lines containing %q", m.term)) +
+		helpStyle.Render(fmt.Sprintf("  in %d pages matching %s", m.searched, m.keyword)) + "\n\n"
+
+	if len(m.hits) == 0 {
+		s += normalStyle.Render("  No matches") + "\n\n"
+		return s + helpStyle.Render("q quit")
+	}
+
+	// Pad page names to a common width so the lines line up
+	pageW := 0
+	for _, hit := range m.hits {
+		pageW = max(pageW, len(pageLabel(hit.Page)))
+	}
+
+	endIdx := min(m.scrollOffset+m.viewportHeight(), len(m.hits))
+	for i := m.scrollOffset; i < endIdx; i++ {
+		hit := m.hits[i]
+		prefix := "  "
+		style, highlight := normalStyle, matchStyle
+		if i == m.cursor {
+			prefix = "> "
+			style, highlight = selectedStyle, selectedMatchStyle
+		}
+		prefix += fmt.Sprintf("%-*s %5d  ", pageW, pageLabel(hit.Page), hit.Line+1)
+		text := hit.Text
+		if m.width > 0 {
+			text = ansi.Truncate(text, max(m.width-len(prefix), 0), "…")
+		}
+		s += style.Render(prefix) + highlightRanges(text, termRanges(text, m.term), style, highlight) + "\n"
+	}
+
+	s += "\n" + helpStyle.Render(fmt.Sprintf("[%d/%d] ↑/k up • ↓/j down • enter open at line • q quit", m.cursor+1, len(m.hits)))
+	return s
+}
+
+// pageLabel names a page without its description, e.g. "ssh(1)"
+func pageLabel(page search.ManPage) string {
+	if page.Section == "" {
+		return page.Name
+	}
+	return page.Name + "(" + page.Section + ")"
+}
+
+// termRanges returns the byte ranges of the case-insensitive occurrences of
+// term in text
+func termRanges(text, term string) [][2]int {
+	if term == "" {
+		return nil
+	}
+	lower, term := strings.ToLower(text), strings.ToLower(term)
+	var ranges [][2]int
+	for offset := 0; ; {
+		idx := strings.Index(lower[offset:], term)
+		if idx == -1 {
+			return ranges
+		}
+		start := offset + idx
+		ranges = append(ranges, [2]int{start, start + len(term)})
+		offset = start + len(term)
+	}
+}
+
+// Selected returns the hit picked to open, or nil if none was picked
+func (m GrepModel) Selected() *GrepHit {
+	return m.selected
+}
@@ -0,0 +1,98 @@
+package search
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// defaultGridWidth is the width the result grid is laid out for until the
+// first WindowSizeMsg
+const defaultGridWidth = 80
+
+// gridGap separates the columns of the result grid
+const gridGap = 2
+
+// WithColumns returns a copy of the model that lists results as a grid of
+// name(section) entries, like ls, instead of one per line. The highlighted
+// result's description is shown below the grid.
+func (m Model) WithColumns(on bool) Model {
+	m.compact = on
+	m.scrollOffset = 0
+	m.adjustScroll()
+	return m
+}
+
+// gridColumns returns how many results each row of the list shows: one in
+// the normal list, as many as fit the terminal in the grid
+func (m Model) gridColumns() int {
+	if !m.compact {
+		return 1
+	}
+	width := m.width
+	if width == 0 {
+		width = defaultGridWidth
+	}
+	return max((width+gridGap)/(m.gridCellWidth()+gridGap), 1)
+}
+
+// gridCellWidth returns the width of a grid cell: the cursor marker, a
+// favorite star, and the longest name(section)
+func (m Model) gridCellWidth() int {
+	longest := 0
+	for _, page := range m.pages {
+		longest = max(longest, lipgloss.Width(pageLabel(page)))
+	}
+	width := len("> ") + longest
+	if m.store != nil {
+		width += lipgloss.Width("★ ")
+	}
+	return width
+}
+
+// viewGrid renders the visible rows of the result grid
+func (m Model) viewGrid() string {
+	cols := m.gridColumns()
+	cellWidth := m.gridCellWidth()
+	rows := (len(m.pages) + cols - 1) / cols
+	endRow := min(m.scrollOffset+m.viewportHeight(), rows)
+
+	var s strings.Builder
+	for row := m.scrollOffset; row < endRow; row++ {
+		for col := range cols {
+			i := row*cols + col
+			if i >= len(m.pages) {
+				break
+			}
+			if col > 0 {
+				s.WriteString(strings.Repeat(" ", gridGap))
+			}
+			cell := m.renderGridCell(i)
+			s.WriteString(cell)
+			if col < cols-1 {
+				s.WriteString(strings.Repeat(" ", max(cellWidth-lipgloss.Width(cell), 0)))
+			}
+		}
+		s.WriteString("\n")
+	}
+	return s.String()
+}
+
+// renderGridCell renders result i as a grid cell, without padding
+func (m Model) renderGridCell(i int) string {
+	page := m.pages[i]
+	prefix := "  "
+	style, highlight := normalStyle, matchStyle
+	if i == m.cursor {
+		prefix = "> "
+		style, highlight = selectedStyle, selectedMatchStyle
+	}
+	if m.isFavorite(page) {
+		prefix += "★ "
+	} else if m.store != nil {
+		prefix += "  "
+	}
+	label := pageLabel(page)
+	ranges := m.matcher.Ranges(label)
+	return style.Render(prefix) + highlightRanges(label, ranges, style, highlight)
+}
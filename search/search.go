@@ -2,9 +2,12 @@ package search
 
 import (
 	"fmt"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/shadyabhi/mantee/clipboard"
+	"github.com/shadyabhi/mantee/history"
 	"github.com/shadyabhi/mantee/man/search"
 )
 
@@ -17,6 +20,16 @@ var (
 	normalStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("252"))
 
+	// Keyword matches within a result, on a normal and the selected row
+	// (which keeps its background so the row still reads as selected)
+	matchStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("208"))
+
+	selectedMatchStyle = selectedStyle.
+				Foreground(lipgloss.Color("214")).
+				Underline(true)
+
 	helpStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("241"))
 
@@ -32,6 +45,13 @@ var (
 			Foreground(lipgloss.Color("196"))
 )
 
+// Smallest terminal the search prompt and result list render in: the title,
+// help line and a few results
+const (
+	minWidth  = 20
+	minHeight = 7
+)
+
 // uiState represents the current state of the UI
 type uiState int
 
@@ -46,30 +66,134 @@ type Model struct {
 	input        string
 	pages        []search.ManPage
 	cursor       int
-	scrollOffset int // Scroll offset for viewport
+	scrollOffset int  // Scroll offset for viewport, in rows of the grid when compact
+	compact      bool // List results as a grid of name(section) entries
 	selected     *search.ManPage
 	quitting     bool
 	keyword      string
+	total        int                  // Results found before opts.MaxResults capped pages
+	opts         search.SearchOptions // How keywords are matched (keyword, regex, wildcard)
+	matcher      search.Matcher       // Highlights keyword in results, compiled once per search
 	err          string
+	status       string           // Result of the last copy, cleared by the next key
+	recent       []search.ManPage // Recently opened pages shown on the empty input screen
+	recentCursor int              // Selection within the quick list (favorites, then recent pages)
+	favorites    []search.ManPage // Pinned pages shown above the recent ones
+	store        *history.Store   // Where favorites are toggled (nil disables pinning)
 	width        int
 	height       int
 }
 
 // New creates a new Model starting with text input
-func New() Model {
+func New(opts search.SearchOptions) Model {
 	return Model{
 		state: stateInput,
+		opts:  opts,
 	}
 }
 
-// NewWithResults creates a new Model starting with selection (when keyword provided via CLI)
-func NewWithResults(keyword string, pages []search.ManPage) Model {
+// NewWithResults creates a new Model starting with selection (when keyword
+// provided via CLI). total is the number of results before the cap.
+func NewWithResults(keyword string, pages []search.ManPage, total int, opts search.SearchOptions) Model {
 	return Model{
 		state:   stateSelect,
 		pages:   pages,
 		cursor:  0,
 		keyword: keyword,
+		total:   total,
+		opts:    opts,
+		matcher: opts.Matcher(keyword),
+	}
+}
+
+// WithRecent returns a copy of the model that lists the given recently opened
+// pages below the empty search prompt for quick launch
+func (m Model) WithRecent(pages []search.ManPage) Model {
+	m.recent = pages
+	m.recentCursor = 0
+	return m
+}
+
+// WithFavorites returns a copy of the model that lists the pinned pages from
+// store above the recent ones and lets pages be pinned or unpinned
+func (m Model) WithFavorites(store *history.Store) Model {
+	m.store = store
+	m.favorites, _ = store.Favorites()
+	m.recentCursor = 0
+	return m
+}
+
+// quickList returns the pages offered on the empty input screen: favorites
+// first, then recent pages that are not favorites
+func (m Model) quickList() []search.ManPage {
+	pages := append([]search.ManPage(nil), m.favorites...)
+	for _, page := range m.recent {
+		if !m.isFavorite(page) {
+			pages = append(pages, page)
+		}
 	}
+	return pages
+}
+
+// isFavorite reports whether page is pinned. Descriptions are ignored since
+// they differ between man -k output and stored entries.
+func (m Model) isFavorite(page search.ManPage) bool {
+	for _, fav := range m.favorites {
+		if fav.Name == page.Name && fav.Section == page.Section {
+			return true
+		}
+	}
+	return false
+}
+
+// togglePin pins or unpins page and reloads the favorites
+func (m *Model) togglePin(page search.ManPage) {
+	if m.store == nil {
+		return
+	}
+	if _, err := m.store.TogglePin(page); err != nil {
+		m.err = fmt.Sprintf("Updating favorites: %v", err)
+		return
+	}
+	m.favorites, _ = m.store.Favorites()
+}
+
+// WithNoResults returns a copy of the model on the input screen with keyword
+// pre-filled and an inline "no results" message, so a CLI keyword that found
+// nothing can be edited and retried
+func (m Model) WithNoResults(keyword string) Model {
+	m.state = stateInput
+	m.input = keyword
+	m.err = noResultsMessage(keyword)
+	return m
+}
+
+// WithNotFound returns a copy of the model for a selected page man has no
+// entry for: suggestions (similar pages) to pick from instead, or the input
+// screen with the name pre-filled when there are none
+func (m Model) WithNotFound(page search.ManPage, suggestions []search.ManPage) Model {
+	m.selected = nil
+	m.quitting = false
+	if len(suggestions) == 0 {
+		m = m.WithNoResults(page.Name)
+		m.err = fmt.Sprintf("No manual entry for %s (edit and retry)", pageLabel(page))
+		return m
+	}
+	m.state = stateSelect
+	m.keyword = page.Name
+	m.matcher = m.opts.Matcher(m.keyword)
+	m.pages = suggestions
+	m.total = len(suggestions)
+	m.cursor = 0
+	m.scrollOffset = 0
+	m.err = fmt.Sprintf("No manual entry for %s; pick a similar page", pageLabel(page))
+	return m
+}
+
+// showingRecent reports whether the quick list (favorites and recent pages)
+// is active
+func (m Model) showingRecent() bool {
+	return m.input == "" && len(m.quickList()) > 0
 }
 
 // Init implements tea.Model
@@ -83,6 +207,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		// The grid's column count, and so the cursor's row, follow the width
+		m.adjustScroll()
+		return m, nil
+	case copiedMsg:
+		if msg.err != nil {
+			m.err = fmt.Sprintf("Copying results: %v", msg.err)
+		} else {
+			m.status = fmt.Sprintf("Copied %d results", msg.count)
+		}
 		return m, nil
 	case tea.KeyMsg:
 		switch m.state {
@@ -101,24 +234,52 @@ func (m Model) updateInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.quitting = true
 		return m, tea.Quit
 
+	case "up":
+		if m.showingRecent() && m.recentCursor > 0 {
+			m.recentCursor--
+		}
+		return m, nil
+
+	case "down":
+		if m.showingRecent() && m.recentCursor < len(m.quickList())-1 {
+			m.recentCursor++
+		}
+		return m, nil
+
+	case "ctrl+f":
+		// Pin or unpin the highlighted quick list page
+		if m.showingRecent() {
+			m.togglePin(m.quickList()[m.recentCursor])
+			m.recentCursor = min(m.recentCursor, max(len(m.quickList())-1, 0))
+		}
+		return m, nil
+
 	case "enter":
+		if m.showingRecent() {
+			// Open the highlighted page directly
+			page := m.quickList()[m.recentCursor]
+			m.selected = &page
+			return m, tea.Quit
+		}
 		if m.input == "" {
 			return m, nil
 		}
 		// Search for man pages
-		pages, err := search.SearchManPages(m.input)
+		pages, total, err := search.SearchManPages(m.input, m.opts)
 		if err != nil {
 			m.err = fmt.Sprintf("Error searching: %v", err)
 			return m, nil
 		}
 		if len(pages) == 0 {
-			m.err = fmt.Sprintf("No man pages found for: %s", m.input)
+			m.err = noResultsMessage(m.input)
 			return m, nil
 		}
 		// Transition to selection state
 		m.state = stateSelect
 		m.keyword = m.input
+		m.matcher = m.opts.Matcher(m.keyword)
 		m.pages = pages
+		m.total = total
 		m.cursor = 0
 		m.scrollOffset = 0 // Reset scroll to top
 		m.err = ""
@@ -140,30 +301,83 @@ func (m Model) updateInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// noResultsMessage is the inline message shown when a search finds nothing
+func noResultsMessage(keyword string) string {
+	return fmt.Sprintf("No man pages found for: %s (edit and retry)", keyword)
+}
+
+// copiedMsg reports the result of copying the result list to the clipboard
+type copiedMsg struct {
+	count int
+	err   error
+}
+
+// copyResults copies the result list to the clipboard, one page per line
+func (m Model) copyResults() tea.Cmd {
+	pages := m.pages
+	return func() tea.Msg {
+		return copiedMsg{count: len(pages), err: clipboard.Write(search.FormatResults(pages))}
+	}
+}
+
 func (m Model) updateSelect(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.status = ""
 	switch msg.String() {
 	case "ctrl+c", "q", "esc":
 		m.quitting = true
 		return m, tea.Quit
 
 	case "up", "k":
-		if m.cursor > 0 {
-			m.cursor--
+		if cols := m.gridColumns(); m.cursor >= cols {
+			m.cursor -= cols
 			m.adjustScroll()
 		}
 
 	case "down", "j":
-		if m.cursor < len(m.pages)-1 {
+		cols := m.gridColumns()
+		if next := m.cursor + cols; next < len(m.pages) {
+			m.cursor = next
+			m.adjustScroll()
+		} else if m.cursor/cols < (len(m.pages)-1)/cols {
+			// The row below is short: land on its last result
+			m.cursor = len(m.pages) - 1
+			m.adjustScroll()
+		}
+
+	case "left", "h":
+		if m.compact && m.cursor > 0 {
+			m.cursor--
+			m.adjustScroll()
+		}
+
+	case "right", "l":
+		if m.compact && m.cursor < len(m.pages)-1 {
 			m.cursor++
 			m.adjustScroll()
 		}
 
+	case "c":
+		// Switch between the list and the grid
+		m = m.WithColumns(!m.compact)
+
 	case "enter":
 		if len(m.pages) > 0 {
 			m.selected = &m.pages[m.cursor]
 		}
 		return m, tea.Quit
 
+	case "y":
+		// Copy the whole list, e.g. for notes
+		if len(m.pages) > 0 {
+			return m, m.copyResults()
+		}
+
+	case "F":
+		// Pin or unpin the highlighted result
+		if len(m.pages) > 0 {
+			m.togglePin(m.pages[m.cursor])
+		}
+
 	case "home", "g":
 		m.cursor = 0
 		m.scrollOffset = 0
@@ -175,10 +389,15 @@ func (m Model) updateSelect(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-// viewportHeight returns the number of items that fit in the viewport
+// viewportHeight returns the number of items (rows of the grid when compact)
+// that fit in the viewport
 func (m Model) viewportHeight() int {
-	// Reserve lines for: title (2 lines with spacing), help line (2 lines with spacing)
+	// Reserve lines for: title (2 lines with spacing), help line (2 lines with
+	// spacing), and in the grid the highlighted result's description
 	reserved := 4
+	if m.compact {
+		reserved++
+	}
 	if m.height <= reserved {
 		return 10 // Minimum fallback
 	}
@@ -188,10 +407,11 @@ func (m Model) viewportHeight() int {
 // adjustScroll ensures the cursor is visible within the viewport
 func (m *Model) adjustScroll() {
 	vpHeight := m.viewportHeight()
-	if m.cursor < m.scrollOffset {
-		m.scrollOffset = m.cursor
-	} else if m.cursor >= m.scrollOffset+vpHeight {
-		m.scrollOffset = m.cursor - vpHeight + 1
+	row := m.cursor / m.gridColumns()
+	if row < m.scrollOffset {
+		m.scrollOffset = row
+	} else if row >= m.scrollOffset+vpHeight {
+		m.scrollOffset = row - vpHeight + 1
 	}
 }
 
@@ -200,6 +420,10 @@ func (m Model) View() string {
 	if m.quitting || m.selected != nil {
 		return ""
 	}
+	// The size is unknown (zero) until the first WindowSizeMsg
+	if m.width > 0 && (m.width < minWidth || m.height < minHeight) {
+		return fmt.Sprintf("Terminal too small (need at least %dx%d, have %dx%d)", minWidth, minHeight, m.width, m.height)
+	}
 
 	switch m.state {
 	case stateInput:
@@ -211,42 +435,137 @@ func (m Model) View() string {
 }
 
 func (m Model) viewInput() string {
-	s := promptStyle.Render("Search man pages: ") + m.input + "█\n\n"
+	prompt := "Search man pages: "
+	if mode := m.opts.Describe(); mode != "" {
+		prompt = fmt.Sprintf("Search man pages (%s): ", mode)
+	}
+	s := promptStyle.Render(prompt) + m.input + "█\n\n"
 
 	if m.err != "" {
 		s += errorStyle.Render(m.err) + "\n\n"
 	}
 
+	if m.showingRecent() {
+		for i, page := range m.quickList() {
+			switch i {
+			case 0:
+				if len(m.favorites) > 0 {
+					s += titleStyle.Render("Favorites") + "\n"
+					break
+				}
+				s += titleStyle.Render("Recently opened") + "\n"
+			case len(m.favorites):
+				s += "\n" + titleStyle.Render("Recently opened") + "\n"
+			}
+			if i == m.recentCursor {
+				s += selectedStyle.Render("> "+page.String()) + "\n"
+			} else {
+				s += normalStyle.Render("  "+page.String()) + "\n"
+			}
+		}
+		s += "\n"
+		help := "↑/↓ choose • enter open • type to search • esc quit"
+		if m.store != nil {
+			help = "↑/↓ choose • enter open • ctrl+f pin/unpin • type to search • esc quit"
+		}
+		s += helpStyle.Render(help)
+		return s
+	}
+
 	s += helpStyle.Render("enter search • esc quit")
 	return s
 }
 
 func (m Model) viewSelect() string {
-	s := titleStyle.Render(fmt.Sprintf("Search results for: %s", m.keyword)) + "\n\n"
+	title := fmt.Sprintf("Search results for: %s", m.keyword)
+	if mode := m.opts.Describe(); mode != "" {
+		title += " (" + mode + ")"
+	}
+	s := titleStyle.Render(title)
+	if m.total > len(m.pages) {
+		s += helpStyle.Render(fmt.Sprintf("  showing %d of %d — narrow your search", len(m.pages), m.total))
+	}
+	s += "\n\n"
 
-	vpHeight := m.viewportHeight()
-	endIdx := m.scrollOffset + vpHeight
-	if endIdx > len(m.pages) {
-		endIdx = len(m.pages)
+	if m.compact {
+		s += m.viewGrid() + "\n"
+		if len(m.pages) > 0 {
+			s += normalStyle.Render(m.pages[m.cursor].String()) + "\n"
+		}
+	} else {
+		s += m.viewList() + "\n"
+	}
+	if m.err != "" {
+		s += errorStyle.Render(m.err) + "\n"
+	}
+	if m.status != "" {
+		s += helpStyle.Render(m.status) + "\n"
+	}
+	help := "↑/k up • ↓/j down • c columns • y copy list • enter select • q quit"
+	if m.compact {
+		help = "←↑↓→/hjkl move • c list • y copy list • enter select • q quit"
+	}
+	if m.store != nil {
+		help = strings.Replace(help, "enter select", "enter select • F pin/unpin", 1)
 	}
+	s += helpStyle.Render(fmt.Sprintf("[%d/%d] %s", m.cursor+1, len(m.pages), help))
 
+	return s
+}
+
+// viewList renders the visible results one per line
+func (m Model) viewList() string {
+	s := ""
+	endIdx := min(m.scrollOffset+m.viewportHeight(), len(m.pages))
 	for i := m.scrollOffset; i < endIdx; i++ {
 		page := m.pages[i]
-		line := page.String()
+		prefix := "  "
+		style, highlight := normalStyle, matchStyle
 		if i == m.cursor {
-			s += selectedStyle.Render("> "+line) + "\n"
-		} else {
-			s += normalStyle.Render("  "+line) + "\n"
+			prefix = "> "
+			style, highlight = selectedStyle, selectedMatchStyle
+		}
+		if m.isFavorite(page) {
+			prefix += "★ "
 		}
+		line := page.String()
+		ranges := m.matcher.Ranges(line)
+		s += style.Render(prefix) + highlightRanges(line, ranges, style, highlight) + "\n"
 	}
-
-	s += "\n"
-	s += helpStyle.Render(fmt.Sprintf("[%d/%d] ↑/k up • ↓/j down • enter select • q quit", m.cursor+1, len(m.pages)))
-
 	return s
 }
 
+// highlightRanges renders text with base, except the byte ranges (sorted,
+// as returned by Matcher.Ranges) which are rendered with highlight
+func highlightRanges(text string, ranges [][2]int, base, highlight lipgloss.Style) string {
+	var b strings.Builder
+	pos := 0
+	for _, r := range ranges {
+		if r[0] < pos {
+			continue
+		}
+		if r[0] > pos {
+			b.WriteString(base.Render(text[pos:r[0]]))
+		}
+		b.WriteString(highlight.Render(text[r[0]:r[1]]))
+		pos = r[1]
+	}
+	if pos < len(text) {
+		b.WriteString(base.Render(text[pos:]))
+	}
+	return b.String()
+}
+
 // Selected returns the selected man page, or nil if none selected
 func (m Model) Selected() *search.ManPage {
 	return m.selected
 }
+
+// Results returns the search results and the index of the selected page
+// among them. Both are empty when the page was picked from the recent list.
+func (m Model) Results() ([]search.ManPage, int) {
+	if m.state != stateSelect {
+		return nil, 0
+	}
+	return m.pages, m.cursor
+}
@@ -0,0 +1,40 @@
+package viewer
+
+import "time"
+
+const (
+	// accelResetAfter is the longest pause between j/k presses that still
+	// counts as holding the key; a longer one drops back to scrollStep
+	accelResetAfter = 150 * time.Millisecond
+
+	// accelRampEvery is how many repeats raise the step by another scrollStep
+	accelRampEvery = 4
+
+	// accelMaxFactor caps the step at this many times scrollStep
+	accelMaxFactor = 8
+)
+
+// scrollAccelState tracks j/k repeats for scroll acceleration
+type scrollAccelState struct {
+	dir     int       // Direction of the last move (-1 up, 1 down)
+	at      time.Time // When the last move happened
+	repeats int       // Consecutive quick moves in dir before the last one
+}
+
+// accelStep returns how many lines a j/k move in dir goes: scrollStep, or
+// with scroll acceleration on a growing multiple of it while the key is held
+// or pressed in quick succession
+func (v *Viewer) accelStep(dir int) int {
+	if !v.scrollAccel {
+		return v.scrollStep * dir
+	}
+	now := time.Now()
+	if dir == v.accel.dir && now.Sub(v.accel.at) < accelResetAfter {
+		v.accel.repeats++
+	} else {
+		v.accel.repeats = 0
+	}
+	v.accel.dir, v.accel.at = dir, now
+	factor := min(1+v.accel.repeats/accelRampEvery, accelMaxFactor)
+	return v.scrollStep * factor * dir
+}
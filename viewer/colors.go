@@ -0,0 +1,70 @@
+package viewer
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Default search highlight colors (256-color palette)
+const (
+	DefaultCurrentMatchColor = "208" // Bright orange: the current occurrence and its line
+	DefaultMatchColor        = "22"  // Dark green: the background of other matching lines
+)
+
+// Default look of the focused pane
+const (
+	DefaultFocusColor  = "212"    // Pink border
+	DefaultFocusBorder = "double" // Double line border; other panes use a rounded one
+)
+
+// focusBorders maps the config names to border styles for the focused pane
+var focusBorders = map[string]lipgloss.Border{
+	"rounded": lipgloss.RoundedBorder(),
+	"normal":  lipgloss.NormalBorder(),
+	"thick":   lipgloss.ThickBorder(),
+	"double":  lipgloss.DoubleBorder(),
+}
+
+// ValidateFocusBorder returns an error if name is not a known focused pane
+// border ("rounded", "normal", "thick", "double"). Empty means the default.
+func ValidateFocusBorder(name string) error {
+	if _, ok := focusBorders[name]; !ok && name != "" {
+		return fmt.Errorf("unknown border %q (want rounded, normal, thick, or double)", name)
+	}
+	return nil
+}
+
+// paneBorder returns the border style and color of a pane: the focus border
+// when focused, a gray rounded one otherwise
+func (v Viewer) paneBorder(focused bool) (lipgloss.Border, lipgloss.Color) {
+	if focused {
+		return v.focusBorder, v.focusColor
+	}
+	return lipgloss.RoundedBorder(), lipgloss.Color("241")
+}
+
+// hexColorRe matches a "#rrggbb" color
+var hexColorRe = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// ValidateColor returns an error unless color is a 256-color palette number
+// ("208") or a hex color ("#ff8700"). Empty means the default.
+func ValidateColor(color string) error {
+	if color == "" || hexColorRe.MatchString(color) {
+		return nil
+	}
+	if n, err := strconv.Atoi(color); err == nil && n >= 0 && n <= 255 {
+		return nil
+	}
+	return fmt.Errorf("invalid color %q (want 0-255 or #rrggbb)", color)
+}
+
+// colorOr returns color, or fallback when color is empty
+func colorOr(color, fallback string) lipgloss.Color {
+	if color == "" {
+		return lipgloss.Color(fallback)
+	}
+	return lipgloss.Color(color)
+}
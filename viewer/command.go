@@ -0,0 +1,426 @@
+package viewer
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shadyabhi/mantee/man/parse"
+	"github.com/shadyabhi/mantee/man/search"
+)
+
+// commandNames are the commands accepted at the ":" prompt, in completion order
+var commandNames = []string{"goto", "open", "reload", "export", "options", "set", "info", "help", "quit"}
+
+// settingNames are the options accepted by ":set" (prefix "no" to turn off)
+var settingNames = []string{"sync", "linked", "explain", "emphasis", "expandtabs", "squeeze", "reader", "raw", "center", "margins", "smartcase", "matchbg", "accel", "rules"}
+
+func (v Viewer) updateCommand(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		v.quitting = true
+		return v, tea.Quit
+
+	case "esc":
+		v.mode = modeNormal
+		return v, nil
+
+	case "enter":
+		v.mode = modeNormal
+		return v.runCommand(v.commandInput)
+
+	case "tab":
+		v.completeCommand()
+		return v, nil
+
+	case "backspace":
+		if len(v.commandInput) > 0 {
+			v.commandInput = v.commandInput[:len(v.commandInput)-1]
+		}
+		v.commandHint = ""
+		return v, nil
+
+	default:
+		if len(msg.String()) == 1 {
+			v.commandInput += msg.String()
+			v.commandHint = ""
+		}
+		return v, nil
+	}
+}
+
+// completeCommand completes the command name being typed. A unique match is
+// completed in full; otherwise the input is extended to the longest common
+// prefix and the candidates are shown as a hint.
+func (v *Viewer) completeCommand() {
+	if strings.Contains(v.commandInput, " ") {
+		return
+	}
+
+	var candidates []string
+	for _, name := range commandNames {
+		if strings.HasPrefix(name, v.commandInput) {
+			candidates = append(candidates, name)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		v.commandHint = "no matching command"
+	case 1:
+		v.commandInput = candidates[0] + " "
+		v.commandHint = ""
+	default:
+		prefix := candidates[0]
+		for _, c := range candidates[1:] {
+			for !strings.HasPrefix(c, prefix) {
+				prefix = prefix[:len(prefix)-1]
+			}
+		}
+		v.commandInput = prefix
+		v.commandHint = strings.Join(candidates, " ")
+	}
+}
+
+// runCommand parses and executes a ":" command. Errors are reported in the
+// status bar. Commands may be abbreviated to any unique prefix (":q", ":go").
+func (v Viewer) runCommand(input string) (tea.Model, tea.Cmd) {
+	name, arg, _ := strings.Cut(strings.TrimSpace(input), " ")
+	arg = strings.TrimSpace(arg)
+	if name == "" {
+		return v, nil
+	}
+
+	command := resolveCommand(name)
+	switch command {
+	case "goto":
+		if arg == "" {
+			v.statusMsg = "Usage: :goto SECTION"
+			return v, nil
+		}
+		if v.rawView {
+			v.statusMsg = "Not available in raw view (R to return)"
+			return v, nil
+		}
+		idx := v.findManSection(arg)
+		if idx == -1 {
+			v.statusMsg = "No section matching: " + arg
+			return v, nil
+		}
+		v.jumpToLine(v.content.ManSections[idx].StartLine)
+		v.focusPane = paneContent
+		return v, nil
+
+	case "open":
+		fields := strings.Fields(arg)
+		var page search.ManPage
+		switch len(fields) {
+		case 1:
+			page = search.ManPage{Name: fields[0]}
+		case 2:
+			page = search.ManPage{Section: fields[0], Name: fields[1]}
+		default:
+			v.statusMsg = "Usage: :open [SECTION] NAME"
+			return v, nil
+		}
+		return v, v.openPage(page)
+
+	case "export":
+		if arg == "" {
+			v.statusMsg = "Usage: :export FILE"
+			return v, nil
+		}
+		if err := os.WriteFile(arg, []byte(v.content.RawContent), 0o644); err != nil {
+			v.statusMsg = fmt.Sprintf("Export failed: %v", err)
+			return v, nil
+		}
+		v.statusMsg = "Exported to " + arg
+		return v, nil
+
+	case "options":
+		// Option cheatsheet: to a file if given, otherwise the clipboard
+		if arg == "" {
+			return v, v.copyOptionTable()
+		}
+		table := parse.FormatOptionTable(v.content.Sections)
+		if err := os.WriteFile(arg, []byte(table), 0o644); err != nil {
+			v.statusMsg = fmt.Sprintf("Export failed: %v", err)
+			return v, nil
+		}
+		v.statusMsg = fmt.Sprintf("Wrote %d options to %s", len(v.content.Sections), arg)
+		return v, nil
+
+	case "set":
+		return v.runSet(arg)
+
+	case "info":
+		v.statusMsg = v.pageInfo()
+		return v, nil
+
+	case "reload":
+		return v, v.reload()
+
+	case "help":
+		v.mode = modeHelp
+		return v, nil
+
+	case "quit":
+		v.quitting = true
+		return v, tea.Quit
+
+	default:
+		v.statusMsg = "Unknown command: " + name
+		return v, nil
+	}
+}
+
+// resolveCommand returns the command name starting with prefix when it is
+// unambiguous (or an exact name), otherwise an empty string
+func resolveCommand(prefix string) string {
+	var match string
+	for _, name := range commandNames {
+		if name == prefix {
+			return name
+		}
+		if strings.HasPrefix(name, prefix) {
+			if match != "" {
+				return ""
+			}
+			match = name
+		}
+	}
+	return match
+}
+
+// runSet handles ":set OPTION" and ":set noOPTION"
+func (v Viewer) runSet(arg string) (tea.Model, tea.Cmd) {
+	on := true
+	option := arg
+	if rest, ok := strings.CutPrefix(arg, "no"); ok {
+		on = false
+		option = rest
+	}
+
+	switch option {
+	case "sync":
+		v.setSyncSidebar(on)
+		return v, nil
+	case "linked":
+		v.setLinkedScroll(on)
+		return v, nil
+	case "explain":
+		v.setSidebarExplain(on)
+		return v, nil
+	case "emphasis":
+		v.setEmphasis(on)
+		return v, nil
+	case "reader":
+		v.setReaderMode(on)
+		return v, nil
+	case "raw":
+		v.setRawView(on)
+		return v, nil
+	case "center":
+		v.setCenterCursor(on)
+		return v, nil
+	case "margins":
+		v.setCenterContent(on)
+		return v, nil
+	case "smartcase":
+		v.setSmartCase(on)
+		return v, nil
+	case "matchbg":
+		v.setMatchBackgrounds(on)
+		return v, nil
+	case "rules":
+		v.setSectionRules(on)
+		return v, nil
+	case "accel":
+		v.setScrollAccel(on)
+		return v, nil
+	case "expandtabs":
+		return v, v.setExpandTabs(on)
+	case "squeeze":
+		opts := v.fetchOpts
+		opts.SqueezeBlank = on
+		status := "Blank line squeezing on"
+		if !on {
+			status = "Blank line squeezing off"
+		}
+		return v, v.refetch(opts, status)
+	case "":
+		v.statusMsg = "Usage: :set [no]OPTION (" + strings.Join(settingNames, ", ") + ")"
+		return v, nil
+	default:
+		v.statusMsg = "Unknown option: " + option
+		return v, nil
+	}
+}
+
+// findManSection returns the index of the man section best matching name:
+// an exact (case-insensitive) match, then a prefix match, then a fuzzy match.
+// Returns -1 if nothing matches.
+func (v Viewer) findManSection(name string) int {
+	sections := v.content.ManSections
+	for i, ms := range sections {
+		if strings.EqualFold(ms.Name, name) {
+			return i
+		}
+	}
+	for i, ms := range sections {
+		if strings.HasPrefix(strings.ToLower(ms.Name), strings.ToLower(name)) {
+			return i
+		}
+	}
+	for i, ms := range sections {
+		if fuzzyMatch(name, ms.Name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// setSyncSidebar turns sidebar sync on or off
+func (v *Viewer) setSyncSidebar(on bool) {
+	v.syncSidebar = on
+	if on {
+		v.syncSidebarToContent()
+		v.statusMsg = "Sidebar sync on"
+	} else {
+		v.statusMsg = "Sidebar sync off"
+	}
+}
+
+// setLinkedScroll turns linked scroll on or off. Turning it on selects the
+// option at the content cursor right away.
+func (v *Viewer) setLinkedScroll(on bool) {
+	v.linkedScroll = on
+	if on {
+		v.linkSidebarToContent()
+		v.statusMsg = "Linked scroll on"
+	} else {
+		v.statusMsg = "Linked scroll off"
+	}
+}
+
+// setSidebarExplain turns the summary line under each sidebar option on or
+// off
+func (v *Viewer) setSidebarExplain(on bool) {
+	v.sidebarExplain = on
+	v.adjustSidebarScroll()
+	if on {
+		v.statusMsg = "Option summaries on"
+	} else {
+		v.statusMsg = "Option summaries off"
+	}
+}
+
+// setEmphasis turns bold/underline emphasis on or off
+func (v *Viewer) setEmphasis(on bool) {
+	v.emphasis = on
+	if on {
+		v.statusMsg = "Emphasis on"
+	} else {
+		v.statusMsg = "Emphasis off"
+	}
+}
+
+// setMatchBackgrounds turns the background of matching lines other than
+// the current one on or off. The matched terms stay highlighted either way.
+func (v *Viewer) setMatchBackgrounds(on bool) {
+	v.matchBackgrounds = on
+	if on {
+		v.statusMsg = "Match line backgrounds on"
+	} else {
+		v.statusMsg = "Match line backgrounds off (terms still highlighted)"
+	}
+}
+
+// setScrollAccel turns scroll acceleration on or off
+func (v *Viewer) setScrollAccel(on bool) {
+	v.scrollAccel = on
+	if on {
+		v.statusMsg = "Scroll acceleration on"
+	} else {
+		v.statusMsg = "Scroll acceleration off"
+	}
+}
+
+// setSectionRules turns the horizontal rule after man section headers on or off
+func (v *Viewer) setSectionRules(on bool) {
+	v.sectionRules = on
+	if on {
+		v.statusMsg = "Section rules on"
+	} else {
+		v.statusMsg = "Section rules off"
+	}
+}
+
+// setReaderMode turns reader mode on or off. Only the content pane exists in
+// reader mode, so it takes focus.
+func (v *Viewer) setReaderMode(on bool) {
+	v.readerMode = on
+	if on {
+		v.focusPane = paneContent
+		v.statusMsg = "Reader mode on"
+	} else {
+		v.statusMsg = "Reader mode off"
+	}
+}
+
+// setCenterContent turns centering of narrow pages in the content pane on
+// or off
+func (v *Viewer) setCenterContent(on bool) {
+	v.centerContent = on
+	if on {
+		v.statusMsg = "Content margins on"
+	} else {
+		v.statusMsg = "Content margins off"
+	}
+}
+
+// setSmartCase turns smartcase matching on or off, re-running the active
+// search so its matches follow the new casing
+func (v *Viewer) setSmartCase(on bool) {
+	v.smartCase = on
+	if v.searchQuery != "" {
+		if v.searchRegex != nil {
+			// The pattern compiled before, so it compiles again
+			v.searchRegex, _ = compileSearchRegex(v.searchQuery, v.caseSensitive())
+		}
+		v.runSearch(v.searchQuery)
+	}
+	if on {
+		v.statusMsg = "Smartcase on (uppercase in a search matches case)"
+	} else {
+		v.statusMsg = "Smartcase off (searches ignore case)"
+	}
+}
+
+// setCenterCursor turns centered-cursor scrolling on or off. Turning it on
+// recenters right away rather than on the next move.
+func (v *Viewer) setCenterCursor(on bool) {
+	v.centerCursor = on
+	if on {
+		v.moveCursor(0)
+		v.statusMsg = "Center cursor on"
+	} else {
+		v.statusMsg = "Center cursor off"
+	}
+}
+
+// setExpandTabs re-fetches the page with tab expansion on or off
+func (v Viewer) setExpandTabs(on bool) tea.Cmd {
+	opts := v.fetchOpts
+	status := "Tab expansion on"
+	opts.Col = parse.ColExpandTabs
+	if !on {
+		status = "Tab expansion off"
+		opts.Col = parse.ColStrip
+	}
+	if opts.Plain {
+		status = "Rendering with " + opts.Col.String()
+	}
+	return v.refetch(opts, status)
+}
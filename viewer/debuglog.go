@@ -0,0 +1,42 @@
+package viewer
+
+import (
+	"log"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// modeNames names the viewer modes in the debug log
+var modeNames = map[viewerMode]string{
+	modeNormal:        "normal",
+	modeSearch:        "search",
+	modeSectionSelect: "section-select",
+	modeHelp:          "help",
+	modeOutline:       "outline",
+	modeCommand:       "command",
+	modeTable:         "table",
+	modeSynopsis:      "synopsis",
+}
+
+// paneNamesByPane names the panes in the debug log, as in the config
+var paneNamesByPane = map[focusPane]string{
+	paneSidebar:  "options",
+	paneContent:  "content",
+	paneSections: "sections",
+}
+
+// logKey records a key event and any mode or pane change it caused to the
+// debug log (discarded unless --debug-log or MANTEE_DEBUG enabled it)
+func logKey(msg tea.KeyMsg, before Viewer, after tea.Model) {
+	log.Printf("key %q mode=%s pane=%s", msg.String(), modeNames[before.mode], paneNamesByPane[before.focusPane])
+	v, ok := after.(Viewer)
+	if !ok {
+		return
+	}
+	if v.mode != before.mode {
+		log.Printf("mode %s -> %s", modeNames[before.mode], modeNames[v.mode])
+	}
+	if v.focusPane != before.focusPane {
+		log.Printf("pane %s -> %s", paneNamesByPane[before.focusPane], paneNamesByPane[v.focusPane])
+	}
+}
@@ -0,0 +1,179 @@
+package viewer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shadyabhi/mantee/clipboard"
+	"github.com/shadyabhi/mantee/man/parse"
+)
+
+// externalExitMsg is sent when an external pager/editor process exits
+type externalExitMsg struct {
+	err     error
+	cleanup func() // Optional cleanup (e.g. removing a temp file)
+}
+
+// lookupCommand returns the command line from the first non-empty environment
+// variable, falling back to the given default. The result is split on
+// whitespace so values like "less -R" work. Returns nil if the resolved program
+// is not found in PATH.
+func lookupCommand(envVars []string, fallback string) []string {
+	for _, env := range envVars {
+		if value := strings.TrimSpace(os.Getenv(env)); value != "" {
+			args := strings.Fields(value)
+			if _, err := exec.LookPath(args[0]); err == nil {
+				return args
+			}
+		}
+	}
+	if fallback == "" {
+		return nil
+	}
+	if _, err := exec.LookPath(fallback); err != nil {
+		return nil
+	}
+	return []string{fallback}
+}
+
+// openInPager suspends the TUI and pipes the raw man page content into $PAGER
+// (falling back to less)
+func (v Viewer) openInPager() (Viewer, tea.Cmd) {
+	args := lookupCommand([]string{"PAGER"}, "less")
+	if args == nil {
+		v.statusMsg = "No pager found: set $PAGER or install less"
+		return v, nil
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = strings.NewReader(v.content.RawContent)
+	return v, tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return externalExitMsg{err: err}
+	})
+}
+
+// openInEditor writes the raw man page content to a temp file and opens it
+// in $VISUAL/$EDITOR (falling back to vi)
+func (v Viewer) openInEditor() (Viewer, tea.Cmd) {
+	args := lookupCommand([]string{"VISUAL", "EDITOR"}, "vi")
+	if args == nil {
+		v.statusMsg = "No editor found: set $EDITOR"
+		return v, nil
+	}
+
+	f, err := os.CreateTemp("", fmt.Sprintf("mantee-%s.*.txt", v.manPage.Name))
+	if err != nil {
+		v.statusMsg = fmt.Sprintf("Creating temp file: %v", err)
+		return v, nil
+	}
+	_, writeErr := f.WriteString(v.content.RawContent)
+	closeErr := f.Close()
+	if err := errors.Join(writeErr, closeErr); err != nil {
+		os.Remove(f.Name())
+		v.statusMsg = fmt.Sprintf("Writing temp file: %v", err)
+		return v, nil
+	}
+
+	path := f.Name()
+	cmd := exec.Command(args[0], append(args[1:], path)...)
+	return v, tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return externalExitMsg{err: err, cleanup: func() { os.Remove(path) }}
+	})
+}
+
+// locateResultMsg carries the result of resolving the page's source file
+type locateResultMsg struct {
+	paths []string
+	err   error
+}
+
+// togglePin pins the current page as a favorite, or unpins it
+func (v *Viewer) togglePin() {
+	if v.favorites == nil {
+		v.statusMsg = "Favorites are unavailable (no config directory)"
+		return
+	}
+	pinned, err := v.favorites.TogglePin(v.manPage)
+	if err != nil {
+		v.statusMsg = fmt.Sprintf("Updating favorites: %v", err)
+		return
+	}
+	v.pinned = pinned
+	if pinned {
+		v.statusMsg = "Pinned " + v.manPage.Command()
+	} else {
+		v.statusMsg = "Unpinned " + v.manPage.Command()
+	}
+}
+
+// locatePage resolves the source file(s) of the current page via 'man -w'
+func (v Viewer) locatePage() tea.Cmd {
+	section, name, env := v.manPage.Section, v.manPage.Name, v.fetchOpts.Env
+	return func() tea.Msg {
+		paths, err := parse.LocateManPage(section, name, env)
+		return locateResultMsg{paths: paths, err: err}
+	}
+}
+
+// clipboardMsg reports the result of copying text to the clipboard
+type clipboardMsg struct {
+	what string // Short description of what was copied, for the status bar
+	err  error
+}
+
+// copyOptionTable copies the page's options as a flags/summary table, the
+// same output as --list-options
+func (v Viewer) copyOptionTable() tea.Cmd {
+	table := parse.FormatOptionTable(v.content.Sections)
+	return copyToClipboard(table, fmt.Sprintf("%d options", len(v.content.Sections)))
+}
+
+// copySectionRef copies a reference to the man section under the cursor,
+// e.g. "sshd_config(5) ENVIRONMENT"
+func (v *Viewer) copySectionRef() tea.Cmd {
+	idx := v.currentManSectionIndex()
+	if idx == -1 {
+		v.statusMsg = "No man section to copy"
+		return nil
+	}
+	ref := v.pageRef() + " " + v.content.ManSections[idx].Name
+	return copyToClipboard(ref, ref)
+}
+
+// copyExample copies the EXAMPLES code block under the cursor, without the
+// page's indentation
+func (v *Viewer) copyExample() tea.Cmd {
+	currentLine := v.scrollOffset + v.contentCursor
+	for _, block := range v.content.Examples {
+		if currentLine >= block.StartLine && currentLine <= block.EndLine {
+			what := fmt.Sprintf("example (%d lines)", block.EndLine-block.StartLine+1)
+			return copyToClipboard(block.Code(v.content.Lines), what)
+		}
+	}
+	v.statusMsg = "No example under the cursor"
+	return nil
+}
+
+// pageRef returns the page as name(section), using the section man resolved
+// when known, or just the name if there is no section
+func (v Viewer) pageRef() string {
+	section := v.content.Section
+	if section == "" {
+		section = v.manPage.Section
+	}
+	if section == "" {
+		return v.manPage.Name
+	}
+	return v.manPage.Name + "(" + section + ")"
+}
+
+// copyToClipboard copies text to the clipboard in the background
+func copyToClipboard(text, what string) tea.Cmd {
+	return func() tea.Msg {
+		return clipboardMsg{what: what, err: clipboard.Write(text)}
+	}
+}
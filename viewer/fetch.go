@@ -0,0 +1,227 @@
+package viewer
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shadyabhi/mantee/man/parse"
+	"github.com/shadyabhi/mantee/man/search"
+)
+
+// fetchStartedMsg announces a background fetch so the status bar can show a
+// loading indicator while man runs (large pages can take a while)
+type fetchStartedMsg struct {
+	page search.ManPage
+}
+
+// pageFetchedMsg carries freshly fetched content for the current page
+type pageFetchedMsg struct {
+	page      search.ManPage // Page the content belongs to
+	content   *parse.ManPageContent
+	fetchOpts parse.FetchOptions // Options the content was fetched with
+	status    string             // Status message to show on success
+	zoom      zoomChange         // How the zoom state changes on success
+	err       error
+}
+
+// zoomChange is how a re-fetch changes the zoom state once its page arrives
+type zoomChange int
+
+const (
+	zoomKeep zoomChange = iota // Leave the zoom state alone
+	zoomIn                     // The page was re-formatted to the content pane's width
+	zoomOut                    // The page was re-formatted back to its unzoomed width
+)
+
+// refetch re-runs FetchManPage for the current page in the background with
+// the given options. The result arrives as a pageFetchedMsg.
+func (v Viewer) refetch(opts parse.FetchOptions, status string) tea.Cmd {
+	return v.refetchZoom(opts, status, zoomKeep)
+}
+
+// refetchZoom is refetch for z. The zoom state only changes once the
+// re-formatted page arrives, so a failed fetch leaves it as it was.
+func (v Viewer) refetchZoom(opts parse.FetchOptions, status string, zoom zoomChange) tea.Cmd {
+	if v.fromStdin {
+		page := v.manPage
+		return func() tea.Msg { return pageFetchedMsg{page: page, err: errFromStdin} }
+	}
+	return fetchPageZoom(v.manPage, opts, status, zoom)
+}
+
+// errFromStdin is why a page read from stdin can't be reloaded or re-rendered
+var errFromStdin = errors.New("the page was read from stdin; pipe it in again to change how it's formatted")
+
+// reload fetches the current page again with the same options, e.g. after
+// editing its source. applyFetched keeps the reading position.
+func (v Viewer) reload() tea.Cmd {
+	return v.refetch(v.fetchOpts, "Reloaded "+v.manPage.Command())
+}
+
+// stepResult opens the search result delta places from the current one,
+// clamped to the ends of the result list
+func (v *Viewer) stepResult(delta int) tea.Cmd {
+	if len(v.results) == 0 {
+		v.statusMsg = "No search results to step through"
+		return nil
+	}
+	i := v.resultIndex
+	if v.onResult() {
+		i += delta
+	}
+	i = min(max(i, 0), len(v.results)-1)
+	if v.onResult() && i == v.resultIndex {
+		if delta > 0 {
+			v.statusMsg = "Already at the last result"
+		} else {
+			v.statusMsg = "Already at the first result"
+		}
+		return nil
+	}
+	return v.openPage(v.results[i])
+}
+
+// onResult reports whether the page shown is the current search result
+// (false after :open switched to a page outside the results)
+func (v Viewer) onResult() bool {
+	return v.resultIndex < len(v.results) && v.results[v.resultIndex] == v.manPage
+}
+
+// openPage fetches another man page in the background with the current
+// options; the viewer switches to it when the pageFetchedMsg arrives
+func (v Viewer) openPage(page search.ManPage) tea.Cmd {
+	return fetchPage(page, v.fetchOpts, "Opened "+page.Command())
+}
+
+// fetchPage runs FetchManPage in the background, delivering a fetchStartedMsg
+// followed by a pageFetchedMsg
+func fetchPage(page search.ManPage, opts parse.FetchOptions, status string) tea.Cmd {
+	return fetchPageZoom(page, opts, status, zoomKeep)
+}
+
+// fetchPageZoom is fetchPage with the zoom change to apply on success
+func fetchPageZoom(page search.ManPage, opts parse.FetchOptions, status string, zoom zoomChange) tea.Cmd {
+	started := func() tea.Msg { return fetchStartedMsg{page: page} }
+	fetch := func() tea.Msg {
+		content, err := parse.FetchManPage(page.Section, page.Name, opts)
+		return pageFetchedMsg{page: page, content: content, fetchOpts: opts, status: status, zoom: zoom, err: err}
+	}
+	return tea.Sequence(started, fetch)
+}
+
+// pageInfo summarizes the current page for ":info": its size and how long it
+// took to parse
+func (v Viewer) pageInfo() string {
+	return fmt.Sprintf("%s: %d lines • %d options • %d sections • parsed in %v",
+		v.manPage.Command(), len(v.content.Lines), len(v.content.Sections),
+		len(v.content.ManSections), v.content.ParseDuration.Round(time.Millisecond))
+}
+
+// applyFetched swaps in fetched content. For a re-fetch of the current page
+// the reading position is kept at the same relative place and any active
+// search is re-run; a different page starts at the top with search cleared.
+func (v Viewer) applyFetched(msg pageFetchedMsg) Viewer {
+	v.loading = ""
+	if msg.err != nil {
+		v.statusMsg = fmt.Sprintf("Fetching page: %v", msg.err)
+		return v
+	}
+
+	if msg.page != v.manPage {
+		v.manPage = msg.page
+		v.fromStdin = false
+		if i := slices.Index(v.results, msg.page); i != -1 {
+			v.resultIndex = i
+		}
+		v.pinned = v.favorites != nil && v.favorites.IsPinned(msg.page)
+		v.scrollOffset = 0
+		v.contentCursor = 0
+		v.searchQuery = ""
+		v.searchRegex = nil
+		v.searchPhrase = false
+		v.searchScope = nil
+		v.matches = nil
+		v.filteredIndices = nil
+		// The SYNOPSIS overlay showed the old page
+		if v.mode == modeSynopsis {
+			v.mode = modeNormal
+		}
+		v.synopsisScrollOffset = 0
+	}
+
+	// Preserve position by line ratio since line counts change with formatting
+	oldTotal := len(v.lines())
+	v.content = msg.content
+	if v.focusPane == paneSidebar && !v.hasOptions() {
+		v.focusPane = paneContent
+	}
+	if v.rawView {
+		v.rawLines = rawLines(msg.content)
+	}
+	newTotal := len(v.lines())
+	currentLine := v.scrollOffset + v.contentCursor
+	newLine := currentLine
+	if oldTotal > 0 {
+		newLine = currentLine * newTotal / oldTotal
+	}
+
+	v.sectionIndex = parse.NewSectionIndex(msg.content.Sections)
+	switch msg.zoom {
+	case zoomIn:
+		// Keep the width to restore if z was pressed again before the
+		// first zoomed page arrived
+		if !v.zoomed {
+			v.zoomed, v.unzoomedWidth = true, v.fetchOpts.Width
+		}
+	case zoomOut:
+		v.zoomed = false
+	}
+	v.fetchOpts = msg.fetchOpts
+	v.scrollOffset = newLine - v.contentCursor
+	if v.scrollOffset < 0 {
+		v.contentCursor += v.scrollOffset
+		v.scrollOffset = 0
+	}
+	if v.contentCursor < 0 {
+		v.contentCursor = 0
+	}
+
+	// Indices into the old content are stale
+	v.jumpList = nil
+	v.sidebarCursor = 0
+	v.sidebarScrollOffset = 0
+	v.sectionCursor = 0
+	v.sectionScrollOffset = 0
+	v.sectionsPaneScrollOffset = 0
+	v.sectionsPaneCursor = 0
+	v.tableCursor = 0
+	v.tableScrollOffset = 0
+	v.currentMatch = 0
+	if v.searchScope != nil {
+		// Re-resolve the scoped section's line range by name
+		name := v.searchScope.Name
+		v.searchScope = nil
+		for _, ms := range v.content.ManSections {
+			if ms.Name == name {
+				scope := ms
+				v.searchScope = &scope
+				break
+			}
+		}
+	}
+	if v.searchQuery != "" {
+		if v.searchType == searchAll {
+			v.matches = v.findMatches()
+			v.filteredIndices = nil
+		} else {
+			v.filteredIndices = v.findMatchingSections()
+			v.matches = nil
+		}
+	}
+
+	v.statusMsg = msg.status
+	return v
+}
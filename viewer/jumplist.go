@@ -0,0 +1,52 @@
+package viewer
+
+// maxJumpListSize caps the number of remembered positions
+const maxJumpListSize = 100
+
+// jumpPosition is a saved content position in the jump list
+type jumpPosition struct {
+	scrollOffset  int
+	contentCursor int
+}
+
+// pushJump records the current content position so it can be returned to
+// with jumpBack. Consecutive duplicates are not recorded.
+func (v *Viewer) pushJump() {
+	pos := jumpPosition{scrollOffset: v.scrollOffset, contentCursor: v.contentCursor}
+	if n := len(v.jumpList); n > 0 && v.jumpList[n-1] == pos {
+		return
+	}
+	// Copy on append so Viewer values don't share the backing array
+	jumps := make([]jumpPosition, 0, len(v.jumpList)+1)
+	jumps = append(jumps, v.jumpList...)
+	jumps = append(jumps, pos)
+	if len(jumps) > maxJumpListSize {
+		jumps = jumps[len(jumps)-maxJumpListSize:]
+	}
+	v.jumpList = jumps
+}
+
+// jumpBack restores the most recently recorded position.
+// Returns false if the jump list is empty.
+func (v *Viewer) jumpBack() bool {
+	n := len(v.jumpList)
+	if n == 0 {
+		return false
+	}
+	pos := v.jumpList[n-1]
+	v.jumpList = v.jumpList[:n-1]
+	v.scrollOffset = pos.scrollOffset
+	v.contentCursor = pos.contentCursor
+	return true
+}
+
+// jumpToLine records the current position in the jump list, then scrolls so
+// the given line is at the top of the viewport with the cursor on it
+func (v *Viewer) jumpToLine(line int) {
+	v.pushJump()
+	v.scrollOffset = line
+	if v.scrollOffset < 0 {
+		v.scrollOffset = 0
+	}
+	v.contentCursor = 0
+}
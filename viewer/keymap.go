@@ -0,0 +1,122 @@
+package viewer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// shortcut is a key binding as listed in the help modal and by WriteKeys
+type shortcut struct {
+	key  string
+	desc string
+}
+
+// shortcutGroup is a titled category of shortcuts
+type shortcutGroup struct {
+	title     string
+	shortcuts []shortcut
+}
+
+// keymap returns all shortcuts grouped by category. Descriptions that depend
+// on configuration (the search type "/" starts and what enter does in the
+// content pane) use defaultSearch and enter.
+func keymap(defaultSearch searchType, enter enterAction) []shortcutGroup {
+	return []shortcutGroup{
+		{"Navigation", []shortcut{
+			{"↑/k, ↓/j", "Move up/down"},
+			{"←/h, →/l", "Switch panes"},
+			{"tab", "Cycle panes forward"},
+			{"shift+tab", "Cycle panes backward"},
+			{"pgup/ctrl+u", "Page up"},
+			{"pgdown/ctrl+d", "Page down"},
+			{"home", "Go to top"},
+			{"G", "Go to bottom / Open sections"},
+			{"ctrl+g", "Fuzzy-jump to a section (type to filter)"},
+			{"{, }", "Previous/next man section"},
+			{"gn/gs/gd/go", "Jump to NAME/SYNOPSIS/DESCRIPTION/OPTIONS"},
+			{"shift+←/→", "Scroll content horizontally"},
+			{"0, $", "Scroll to line start/end"},
+			{"enter", "Select item / Jump to section"},
+			{"enter (text)", enter.label()},
+			{"space", "Collapse/expand subsections (sections pane)"},
+			{"t", "Outline (sections + options)"},
+			{"T", "Options table (flags | summary)"},
+			{"S", "Show the SYNOPSIS in an overlay"},
+			{"ctrl+o", "Jump back to previous location"},
+			{"ctrl+n/ctrl+p", "Open next/previous search result"},
+		}},
+		{"Search", []shortcut{
+			{"/", defaultSearch.label() + " (default)"},
+			{"f", "Search current section only"},
+			{"o", "Search options (partial)"},
+			{"O", "Search options (exact)"},
+			{"d", "Search descriptions"},
+			{"o:, O:, d:, /, !, w:", "Prefix a query to switch type (options, exact, descriptions, text, regex, whole words)"},
+			{"\\", "Prefix a query to search it as typed (e.g. \\!! or \\/etc)"},
+			{"n", "Next match (from the sidebar selection)"},
+			{"N", "Previous match"},
+			{"J, K", "Next/previous sidebar entry, keeping sidebar focus"},
+			{"ctrl+t", "Re-run search as the next type (all/option/exact/desc)"},
+			{"esc", "Clear search"},
+		}},
+		{"Other", []shortcut{
+			{"p", "Open in $PAGER"},
+			{"e", "Open in $EDITOR"},
+			{"w", "Show source file path"},
+			{"c", "Copy man command"},
+			{"y", "Copy current section reference"},
+			{"Y", "Copy the example under the cursor"},
+			{"F", "Pin/unpin as favorite"},
+			{"C", "Copy all options as a table"},
+			{"ctrl+r", "Reload the page (keeps position)"},
+			{"s", "Toggle sidebar sync"},
+			{"L", "Toggle linked scroll (sidebar and content)"},
+			{"i", "Toggle option summaries in the sidebar"},
+			{"x", "Toggle tab expansion (col -bx)"},
+			{"z", "Zoom: re-format to the content pane's width"},
+			{"b", "Toggle bold/underline emphasis"},
+			{"B", "Toggle backgrounds of other matching lines"},
+			{"r", "Toggle reader mode (content only)"},
+			{"R", "Toggle raw view (unparsed man output)"},
+			{":", "Command prompt (goto, open, reload, export, options, set, info, help, quit)"},
+			{"ctrl+s", "Back to the search prompt"},
+			{"?", "Show this help"},
+			{"q", "Quit"},
+		}},
+	}
+}
+
+// keyColumnWidth is the width keys are padded to in shortcut listings
+const keyColumnWidth = 14
+
+// padKey pads a key label to keyColumnWidth columns. Padding counts runes
+// since labels contain arrows.
+func padKey(key string) string {
+	if n := utf8.RuneCountInString(key); n < keyColumnWidth {
+		return key + strings.Repeat(" ", keyColumnWidth-n)
+	}
+	return key
+}
+
+// WriteKeys writes the viewer's keyboard shortcuts to w as plain text,
+// grouped by category as in the help modal
+func WriteKeys(w io.Writer, opts Options) error {
+	for i, group := range keymap(searchTypeNames[opts.DefaultSearch], enterActionNames[opts.EnterAction]) {
+		if i > 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintln(w, group.title); err != nil {
+			return err
+		}
+		for _, s := range group.shortcuts {
+			if _, err := fmt.Fprintf(w, "  %s %s\n", padKey(s.key), s.desc); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
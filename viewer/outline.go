@@ -0,0 +1,254 @@
+package viewer
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/shadyabhi/mantee/man/parse"
+)
+
+// outlineEntry is a single row in the outline modal: either a major man
+// section or an option nested under the man section that contains it
+type outlineEntry struct {
+	label string // Section name or option flags
+	line  int    // Line to jump to
+	depth int    // 0 for man sections, 1 for options
+}
+
+// buildOutline combines ManSections and option Sections into a tree, nesting
+// each option under the man section whose line range contains it
+func (v Viewer) buildOutline() []outlineEntry {
+	var entries []outlineEntry
+	optIdx := 0
+	options := v.content.Sections
+
+	// Options that appear before the first man section (rare) go at the top level
+	for optIdx < len(options) && (len(v.content.ManSections) == 0 || options[optIdx].StartLine < v.content.ManSections[0].StartLine) {
+		entries = append(entries, outlineEntry{
+			label: parse.ExtractOptionFlags(options[optIdx].Option),
+			line:  options[optIdx].StartLine,
+		})
+		optIdx++
+	}
+
+	for _, ms := range v.content.ManSections {
+		entries = append(entries, outlineEntry{label: ms.Name, line: ms.StartLine})
+		for optIdx < len(options) && options[optIdx].StartLine <= ms.EndLine {
+			entries = append(entries, outlineEntry{
+				label: parse.ExtractOptionFlags(options[optIdx].Option),
+				line:  options[optIdx].StartLine,
+				depth: 1,
+			})
+			optIdx++
+		}
+	}
+	return entries
+}
+
+// filteredOutline returns outline entries fuzzy-matching the filter.
+// Parent sections of matching options are kept so the tree stays readable.
+func (v Viewer) filteredOutline() []outlineEntry {
+	entries := v.buildOutline()
+	if v.outlineFilter == "" {
+		return entries
+	}
+
+	var filtered []outlineEntry
+	parent := -1         // Index in entries of the last top-level section
+	parentAdded := false // Whether that parent is already in filtered
+	for i, e := range entries {
+		if e.depth == 0 {
+			parent = i
+			parentAdded = false
+		}
+		if !fuzzyMatch(v.outlineFilter, e.label) {
+			continue
+		}
+		if e.depth > 0 && parent >= 0 && !parentAdded {
+			filtered = append(filtered, entries[parent])
+			parentAdded = true
+		}
+		if e.depth == 0 {
+			parentAdded = true
+		}
+		filtered = append(filtered, e)
+	}
+	return filtered
+}
+
+// fuzzyMatch reports whether all characters of pattern appear in s in order
+// (case-insensitive)
+func fuzzyMatch(pattern, s string) bool {
+	pattern = strings.ToLower(pattern)
+	s = strings.ToLower(s)
+	for _, r := range pattern {
+		idx := strings.IndexRune(s, r)
+		if idx == -1 {
+			return false
+		}
+		s = s[idx+utf8.RuneLen(r):]
+	}
+	return true
+}
+
+// openOutline opens the outline modal with an empty filter
+func (v Viewer) openOutline() Viewer {
+	v.mode = modeOutline
+	v.outlineFilter = ""
+	v.outlineCursor = 0
+	v.outlineScrollOffset = 0
+	return v
+}
+
+func (v Viewer) updateOutline(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	entries := v.filteredOutline()
+
+	switch msg.String() {
+	case "ctrl+c":
+		v.quitting = true
+		return v, tea.Quit
+
+	case "esc":
+		// First esc clears the filter, second closes the modal
+		if v.outlineFilter != "" {
+			v.outlineFilter = ""
+			v.outlineCursor = 0
+			v.outlineScrollOffset = 0
+		} else {
+			v.mode = modeNormal
+		}
+		return v, nil
+
+	case "up", "ctrl+p":
+		if v.outlineCursor > 0 {
+			v.outlineCursor--
+			v.adjustOutlineScroll()
+		}
+		return v, nil
+
+	case "down", "ctrl+n":
+		if v.outlineCursor < len(entries)-1 {
+			v.outlineCursor++
+			v.adjustOutlineScroll()
+		}
+		return v, nil
+
+	case "enter":
+		if len(entries) > 0 {
+			v.jumpToLine(entries[v.outlineCursor].line)
+			v.mode = modeNormal
+			v.focusPane = paneContent
+		}
+		return v, nil
+
+	case "backspace":
+		if len(v.outlineFilter) > 0 {
+			v.outlineFilter = v.outlineFilter[:len(v.outlineFilter)-1]
+			v.outlineCursor = 0
+			v.outlineScrollOffset = 0
+		}
+		return v, nil
+
+	default:
+		// Type to filter
+		if len(msg.String()) == 1 {
+			v.outlineFilter += msg.String()
+			v.outlineCursor = 0
+			v.outlineScrollOffset = 0
+		}
+		return v, nil
+	}
+}
+
+// outlineModalHeight returns the number of visible rows in the outline modal
+func (v Viewer) outlineModalHeight() int {
+	maxHeight := v.height/2 - 4
+	if maxHeight < 5 {
+		maxHeight = 5
+	}
+	return maxHeight
+}
+
+// adjustOutlineScroll ensures the outline cursor is visible in the modal
+func (v *Viewer) adjustOutlineScroll() {
+	modalHeight := v.outlineModalHeight()
+	if v.outlineCursor < v.outlineScrollOffset {
+		v.outlineScrollOffset = v.outlineCursor
+	} else if v.outlineCursor >= v.outlineScrollOffset+modalHeight {
+		v.outlineScrollOffset = v.outlineCursor - modalHeight + 1
+	}
+}
+
+// renderOutlineModal renders the outline modal overlay
+func (v Viewer) renderOutlineModal() string {
+	entries := v.filteredOutline()
+	modalHeight := v.outlineModalHeight()
+	modalWidth := 50
+	innerWidth := modalWidth - 4
+
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Width(innerWidth)
+
+	sectionStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("252")).
+		Width(innerWidth)
+
+	optionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("245")).
+		Width(innerWidth)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("212")).
+		Width(innerWidth).
+		Align(lipgloss.Center)
+
+	var lines []string
+	lines = append(lines, titleStyle.Render("Outline"))
+	lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("212")).Render("> ")+v.outlineFilter+"█")
+	lines = append(lines, strings.Repeat("─", innerWidth))
+
+	for i := 0; i < modalHeight; i++ {
+		idx := v.outlineScrollOffset + i
+		if idx >= len(entries) {
+			lines = append(lines, optionStyle.Render(""))
+			continue
+		}
+		e := entries[idx]
+		label := e.label
+		if e.depth > 0 {
+			label = "  └ " + label
+		}
+		label = truncateOption(label, innerWidth-2)
+
+		switch {
+		case idx == v.outlineCursor:
+			lines = append(lines, selectedStyle.Render("> "+label))
+		case e.depth == 0:
+			lines = append(lines, sectionStyle.Render("  "+label))
+		default:
+			lines = append(lines, optionStyle.Render("  "+label))
+		}
+	}
+
+	lines = append(lines, strings.Repeat("─", innerWidth))
+	footerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Width(innerWidth).
+		Align(lipgloss.Center)
+	lines = append(lines, footerStyle.Render("type to filter • ↑↓ navigate • enter jump • esc close"))
+
+	modalStyle := lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("212")).
+		Padding(0, 1).
+		Width(modalWidth)
+
+	return modalStyle.Render(strings.Join(lines, "\n"))
+}
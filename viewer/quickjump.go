@@ -0,0 +1,72 @@
+package viewer
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// quickJump is a key that, after the g leader, jumps to a common man section
+type quickJump struct {
+	key     string
+	section string
+}
+
+// quickJumps are the sections reachable with g followed by their key
+var quickJumps = []quickJump{
+	{"n", "NAME"},
+	{"s", "SYNOPSIS"},
+	{"d", "DESCRIPTION"},
+	{"o", "OPTIONS"},
+}
+
+// quickJumpHint lists the quick jump keys for the status bar
+func quickJumpHint() string {
+	var parts []string
+	for _, q := range quickJumps {
+		parts = append(parts, q.key+" "+q.section)
+	}
+	return "g: " + strings.Join(parts, " • ") + " • esc cancel"
+}
+
+// updateQuickJump handles the key after g: jump to the section it names, or
+// cancel on any other key
+func (v Viewer) updateQuickJump(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v.pendingQuickJump = false
+	if msg.String() == "ctrl+c" {
+		v.quitting = true
+		return v, tea.Quit
+	}
+	for _, q := range quickJumps {
+		if msg.String() != q.key {
+			continue
+		}
+		idx := v.quickJumpSection(q.section)
+		if idx == -1 {
+			v.statusMsg = "No " + q.section + " section"
+			return v, nil
+		}
+		v.jumpToLine(v.content.ManSections[idx].StartLine)
+		v.focusPane = paneContent
+		return v, nil
+	}
+	return v, nil
+}
+
+// quickJumpSection returns the index of the man section named name, or
+// starting with it (e.g. "OPTIONS AND ARGUMENTS"). Unlike :goto there is no
+// fuzzy fallback, so a missing section doesn't jump somewhere unrelated.
+// Returns -1 if there is none.
+func (v Viewer) quickJumpSection(name string) int {
+	for i, ms := range v.content.ManSections {
+		if strings.EqualFold(ms.Name, name) {
+			return i
+		}
+	}
+	for i, ms := range v.content.ManSections {
+		if strings.HasPrefix(strings.ToUpper(ms.Name), name) {
+			return i
+		}
+	}
+	return -1
+}
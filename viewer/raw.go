@@ -0,0 +1,122 @@
+package viewer
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/shadyabhi/mantee/man/parse"
+)
+
+// rawUnavailableKeys are the normal-mode keys that rely on parsed option or
+// man sections, which the raw view doesn't have
+var rawUnavailableKeys = map[string]bool{
+	"f": true, "o": true, "O": true, "d": true, "t": true, "y": true,
+	"{": true, "}": true, "[": true, "]": true, "g": true, "ctrl+g": true, "T": true, "ctrl+t": true, "Y": true, "S": true,
+}
+
+// lines returns the lines the content pane shows: man's output verbatim in
+// the raw view, or the parsed (possibly squeezed) lines otherwise
+func (v Viewer) lines() []string {
+	if v.rawView {
+		return v.rawLines
+	}
+	return v.content.Lines
+}
+
+// contentOnly reports whether the side panes are hidden (reader mode or the
+// raw view)
+func (v Viewer) contentOnly() bool {
+	return v.readerMode || v.rawView
+}
+
+// rawLines splits the page as man printed it, before blank lines were
+// squeezed
+func rawLines(content *parse.ManPageContent) []string {
+	return strings.Split(content.RawContent, "\n")
+}
+
+// rawLineMap maps each parsed line index to the index of the same line in
+// raw. Parsed lines are raw lines with some blank lines dropped, so they are
+// matched in order.
+func rawLineMap(lines, raw []string) []int {
+	lineMap := make([]int, len(lines))
+	j := 0
+	for i, line := range lines {
+		for j < len(raw)-1 && raw[j] != line {
+			j++
+		}
+		lineMap[i] = j
+		j = min(j+1, max(len(raw)-1, 0))
+	}
+	return lineMap
+}
+
+// setRawView switches between the parsed layout and the raw view. The
+// position (and jump list) is converted between parsed and raw line numbers
+// so the same text stays under the cursor. A full-text search is re-run
+// against the new lines; option, description and section-scoped searches
+// depend on parsing, so they are cleared.
+func (v *Viewer) setRawView(on bool) {
+	if on == v.rawView {
+		return
+	}
+
+	raw := rawLines(v.content)
+	lineMap := rawLineMap(v.content.Lines, raw)
+	convert := func(line int) int {
+		if len(lineMap) == 0 {
+			return 0
+		}
+		if on {
+			return lineMap[min(max(line, 0), len(lineMap)-1)]
+		}
+		// The last parsed line at or before the raw line
+		i, found := slices.BinarySearch(lineMap, line)
+		if !found {
+			i--
+		}
+		return max(i, 0)
+	}
+
+	top := convert(v.scrollOffset)
+	cursor := convert(v.scrollOffset + v.contentCursor)
+	// Build a new slice so Viewer values don't share the backing array
+	var jumps []jumpPosition
+	for _, pos := range v.jumpList {
+		jumpTop := convert(pos.scrollOffset)
+		jumps = append(jumps, jumpPosition{
+			scrollOffset:  jumpTop,
+			contentCursor: convert(pos.scrollOffset+pos.contentCursor) - jumpTop,
+		})
+	}
+	v.jumpList = jumps
+
+	v.rawView = on
+	v.rawLines = nil
+	if on {
+		v.rawLines = raw
+		v.focusPane = paneContent
+		v.statusMsg = "Raw view on (R to return)"
+	} else {
+		v.statusMsg = "Raw view off"
+	}
+	v.scrollOffset = top
+	v.contentCursor = max(cursor-top, 0)
+	v.moveCursor(0)
+
+	if v.searchQuery == "" {
+		return
+	}
+	if v.searchType == searchAll && v.searchScope == nil {
+		v.matches = v.findMatches()
+		v.currentMatch = min(v.currentMatch, max(len(v.matches)-1, 0))
+	} else {
+		v.searchQuery = ""
+		v.searchRegex = nil
+		v.searchPhrase = false
+		v.searchScope = nil
+		v.filteredIndices = nil
+		v.matches = nil
+		v.currentMatch = 0
+	}
+}
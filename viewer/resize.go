@@ -0,0 +1,83 @@
+package viewer
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// resizeDebounce is how long the terminal size must stay unchanged before
+// relayout runs. Drag-resizing emits a flood of WindowSizeMsgs; only the
+// last one triggers the work.
+const resizeDebounce = 100 * time.Millisecond
+
+// resizeSettledMsg is delivered resizeDebounce after a WindowSizeMsg. It is
+// stale (and ignored) if another resize arrived in the meantime.
+type resizeSettledMsg struct {
+	seq int
+}
+
+// handleResize records the new size, which is all rendering needs, and
+// schedules relayout once resizing settles
+func (v Viewer) handleResize(msg tea.WindowSizeMsg) (Viewer, tea.Cmd) {
+	v.width = msg.Width
+	v.height = msg.Height
+	v.resizeSeq++
+	seq := v.resizeSeq
+	return v, tea.Tick(resizeDebounce, func(time.Time) tea.Msg {
+		return resizeSettledMsg{seq: seq}
+	})
+}
+
+// relayout adapts size-dependent state after the terminal size settles.
+// Work that depends on the size (re-flowing or re-fetching content) belongs
+// here rather than in its own throttle.
+func (v Viewer) relayout() (Viewer, tea.Cmd) {
+	// Keep the cursor line in view when the viewport shrank
+	if vpHeight := v.viewportHeight(); vpHeight > 0 && v.contentCursor >= vpHeight {
+		v.scrollOffset += v.contentCursor - (vpHeight - 1)
+		v.contentCursor = vpHeight - 1
+	}
+	// Don't leave rows empty below the last line, e.g. when opened at a
+	// line near the end; the cursor stays on its line
+	if maxScroll := max(len(v.lines())-v.viewportHeight(), 0); v.scrollOffset > maxScroll {
+		v.contentCursor = min(v.contentCursor+v.scrollOffset-maxScroll, v.viewportHeight()-1)
+		v.scrollOffset = maxScroll
+	}
+	v.hScrollOffset = min(v.hScrollOffset, v.maxHScroll())
+	v.adjustSidebarScroll()
+	v.adjustSectionScroll()
+	v.adjustSectionsPaneScroll()
+	v.adjustOutlineScroll()
+	return v, nil
+}
+
+const (
+	// minContentWidth is the narrowest content pane worth rendering
+	minContentWidth = 20
+	// minHeight fits the title, pane titles and status bar around a few
+	// content lines
+	minHeight = 8
+)
+
+// minSize returns the smallest terminal the current layout fits: the side
+// panes, their borders and minContentWidth, or just the content pane in
+// reader mode and the raw view
+func (v Viewer) minSize() (width, height int) {
+	width = minContentWidth + 1 // Content pane border
+	if !v.contentOnly() {
+		width += v.sidebarWidth() + v.sectionsPaneWidth() + 2
+	}
+	return width, minHeight
+}
+
+// tooSmallView is shown instead of the layout while the terminal is smaller
+// than minSize, which would otherwise garble (or break) rendering
+func (v Viewer) tooSmallView(width, height int) string {
+	msg := fmt.Sprintf("Terminal too small (need at least %dx%d, have %dx%d)", width, height, v.width, v.height)
+	if !v.contentOnly() {
+		msg += "\nPress r for reader mode, which needs less width"
+	}
+	return msg
+}
@@ -0,0 +1,98 @@
+package viewer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/shadyabhi/mantee/man/parse"
+)
+
+// searchPrefixes are the prefixes that pick a search type from the search
+// box, whichever key opened it
+var searchPrefixes = []struct {
+	prefix string
+	typ    searchType
+}{
+	{"o:", searchOption},
+	{"O:", searchOptionExact},
+	{"d:", searchDescription},
+	{"/", searchAll},
+	{"!", searchAll},  // Regex; see parseSearchInput
+	{"w:", searchAll}, // Whole words
+}
+
+// parseSearchInput splits a prefix off the search box input: "o:term"
+// searches options, "O:term" options exactly, "d:term" descriptions, "/term"
+// the full text, "!pattern" the full text by regex and "w:term" the full
+// text for whole words. Without a prefix the search type the box was opened
+// with (fallback) is used. A leading backslash searches the rest as is, so
+// "\!!" finds "!!" and "\/etc" finds "/etc".
+func parseSearchInput(input string, fallback searchType) (typ searchType, query string, regex, wholeWord bool) {
+	if rest, ok := strings.CutPrefix(input, `\`); ok {
+		return fallback, rest, false, false
+	}
+	for _, p := range searchPrefixes {
+		if strings.HasPrefix(input, p.prefix) {
+			return p.typ, input[len(p.prefix):], p.prefix == "!", p.prefix == "w:"
+		}
+	}
+	return fallback, input, false, false
+}
+
+// compileSearchRegex compiles a regex search, case-insensitively like the
+// other searches unless caseSensitive
+func compileSearchRegex(pattern string, caseSensitive bool) (*regexp.Regexp, error) {
+	if !caseSensitive {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex: %w", err)
+	}
+	return re, nil
+}
+
+// regexOccurrences returns the non-empty matches of re in text, ordered by
+// offset. Line is left unset.
+func regexOccurrences(text string, re *regexp.Regexp) []searchMatch {
+	var matches []searchMatch
+	for _, loc := range re.FindAllStringIndex(text, -1) {
+		if loc[0] < loc[1] {
+			matches = append(matches, searchMatch{start: loc[0], end: loc[1]})
+		}
+	}
+	return matches
+}
+
+// submitSearch runs the search box input, switching search type by its
+// prefix (see parseSearchInput)
+func (v *Viewer) submitSearch(input string) {
+	typ, query, regex, wholeWord := parseSearchInput(input, v.searchType)
+	if typ != searchAll {
+		if v.rawView {
+			v.statusMsg = "Only full-text search works in raw view"
+			return
+		}
+		// A section scope only applies to full-text search
+		v.searchScope = nil
+	}
+
+	// Whole-word matching lasts for this search only
+	v.wholeWord = wholeWord
+	v.searchPhrase = false
+	v.searchRegex = nil
+	if regex && query != "" {
+		re, err := compileSearchRegex(query, v.smartCase && parse.CaseSensitive(query))
+		if err != nil {
+			// The box already switched the search type, so the previous
+			// search can't be kept as it was
+			v.clearSearch()
+			v.statusMsg = err.Error()
+			return
+		}
+		v.searchRegex = re
+	}
+	v.searchType = typ
+	v.runSearch(query)
+}
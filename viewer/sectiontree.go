@@ -0,0 +1,115 @@
+package viewer
+
+import "github.com/shadyabhi/mantee/man/parse"
+
+// sectionRow is a row of the sections pane: a man section, or one of its
+// subsections
+type sectionRow struct {
+	section int // Index into ManSections
+	sub     int // Index into the section's Subsections, -1 for the section itself
+}
+
+// sectionsPaneRows lists the rows of the sections pane: each man section
+// followed by its subsections, unless it is collapsed
+func (v Viewer) sectionsPaneRows() []sectionRow {
+	var rows []sectionRow
+	for i, ms := range v.content.ManSections {
+		rows = append(rows, sectionRow{section: i, sub: -1})
+		if v.collapsedSections[ms.Name] {
+			continue
+		}
+		for j := range ms.Subsections {
+			rows = append(rows, sectionRow{section: i, sub: j})
+		}
+	}
+	return rows
+}
+
+// rowSection returns the section or subsection a row shows
+func (v Viewer) rowSection(r sectionRow) parse.ManSection {
+	ms := v.content.ManSections[r.section]
+	if r.sub < 0 {
+		return ms
+	}
+	return ms.Subsections[r.sub]
+}
+
+// hasSubsections reports whether any man section has subsections, so the
+// sections pane draws a tree
+func (v Viewer) hasSubsections() bool {
+	for _, ms := range v.content.ManSections {
+		if len(ms.Subsections) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// sectionsPaneRow returns the row showing a section's subsection (sub -1
+// for the section itself), or the section's row while it is collapsed
+func (v Viewer) sectionsPaneRow(section, sub int) int {
+	for i, r := range v.sectionsPaneRows() {
+		if r.section == section && (r.sub == sub || r.sub < 0 && v.collapsedSections[v.content.ManSections[section].Name]) {
+			return i
+		}
+	}
+	return 0
+}
+
+// currentSectionsPaneRow returns the row of the section or subsection the
+// content cursor is in (-1 if there are no sections)
+func (v Viewer) currentSectionsPaneRow() int {
+	idx := v.currentManSectionIndex()
+	if idx < 0 {
+		return -1
+	}
+	currentLine := v.scrollOffset + v.contentCursor
+	sub := -1
+	for j, s := range v.content.ManSections[idx].Subsections {
+		if s.StartLine > currentLine {
+			break
+		}
+		sub = j
+	}
+	return v.sectionsPaneRow(idx, sub)
+}
+
+// toggleSectionCollapsed collapses or expands the subsections of the man
+// section under the sections pane cursor. Collapse state is keyed by
+// section name, so it carries over reloads and re-formatting.
+func (v *Viewer) toggleSectionCollapsed() {
+	rows := v.sectionsPaneRows()
+	if v.sectionsPaneCursor >= len(rows) {
+		return
+	}
+	r := rows[v.sectionsPaneCursor]
+	ms := v.content.ManSections[r.section]
+	if len(ms.Subsections) == 0 {
+		v.statusMsg = ms.Name + " has no subsections"
+		return
+	}
+	if v.collapsedSections == nil {
+		v.collapsedSections = make(map[string]bool)
+	}
+	v.collapsedSections[ms.Name] = !v.collapsedSections[ms.Name]
+	// A collapsed subsection's row is gone; stay on its section
+	v.sectionsPaneCursor = v.sectionsPaneRow(r.section, -1)
+	v.adjustSectionsPaneScroll()
+}
+
+// sectionsPaneMarker returns what the sections pane tree draws before a
+// row's name: ▼ or ▶ for an expanded or collapsed section with
+// subsections, and indentation otherwise
+func (v Viewer) sectionsPaneMarker(r sectionRow) string {
+	ms := v.content.ManSections[r.section]
+	switch {
+	case r.sub >= 0:
+		return "    "
+	case len(ms.Subsections) == 0:
+		return "  "
+	case v.collapsedSections[ms.Name]:
+		return "▶ "
+	default:
+		return "▼ "
+	}
+}
@@ -0,0 +1,118 @@
+package viewer
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/shadyabhi/mantee/man/parse"
+)
+
+// synopsisLines returns the body of the SYNOPSIS section with its shared
+// indentation and surrounding blank lines removed, or nil if the page has
+// no SYNOPSIS (or only an empty one)
+func (v Viewer) synopsisLines() []string {
+	idx := v.quickJumpSection("SYNOPSIS")
+	if idx == -1 {
+		return nil
+	}
+	ms := v.content.ManSections[idx]
+	if ms.EndLine <= ms.StartLine || ms.EndLine >= len(v.content.Lines) {
+		return nil
+	}
+	body := parse.CodeBlock{StartLine: ms.StartLine + 1, EndLine: ms.EndLine}.Code(v.content.Lines)
+	body = strings.Trim(body, "\n")
+	if strings.TrimSpace(body) == "" {
+		return nil
+	}
+	return strings.Split(body, "\n")
+}
+
+// openSynopsis shows the SYNOPSIS overlay, or explains why it can't
+func (v *Viewer) openSynopsis() {
+	if v.synopsisLines() == nil {
+		v.statusMsg = "No SYNOPSIS in this page"
+		return
+	}
+	v.mode = modeSynopsis
+	v.synopsisScrollOffset = 0
+}
+
+func (v Viewer) updateSynopsis(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	maxScroll := max(len(v.synopsisLines())-v.synopsisHeight(), 0)
+	switch msg.String() {
+	case "ctrl+c":
+		v.quitting = true
+		return v, tea.Quit
+
+	case "esc", "q", "S":
+		v.mode = modeNormal
+
+	case "enter":
+		// Show the SYNOPSIS in the page itself
+		if idx := v.quickJumpSection("SYNOPSIS"); idx != -1 {
+			v.jumpToLine(v.content.ManSections[idx].StartLine)
+			v.focusPane = paneContent
+		}
+		v.mode = modeNormal
+
+	case "up", "k":
+		v.synopsisScrollOffset = max(min(v.synopsisScrollOffset, maxScroll)-1, 0)
+
+	case "down", "j":
+		v.synopsisScrollOffset = min(v.synopsisScrollOffset+1, maxScroll)
+	}
+	return v, nil
+}
+
+// synopsisHeight returns the number of SYNOPSIS lines the overlay shows
+func (v Viewer) synopsisHeight() int {
+	return max(v.height-10, 3) // Title bar, status bar, and the modal's borders, title, and footer
+}
+
+// renderSynopsisModal renders the SYNOPSIS in a box sized to its lines
+func (v Viewer) renderSynopsisModal() string {
+	lines := v.synopsisLines()
+	longest := 0
+	for _, line := range lines {
+		longest = max(longest, ansi.StringWidth(line))
+	}
+	innerWidth := min(max(longest, 40), v.width-8)
+	modalWidth := innerWidth + 4
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("212")).
+		Width(innerWidth).
+		Align(lipgloss.Center)
+	lineStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252"))
+
+	title := "SYNOPSIS"
+	if len(lines) > v.synopsisHeight() {
+		title += " (↑↓ to scroll)"
+	}
+	out := []string{titleStyle.Render(title), strings.Repeat("─", innerWidth)}
+	// A re-format or resize can leave the offset past the lines
+	start := min(v.synopsisScrollOffset, max(len(lines)-v.synopsisHeight(), 0))
+	end := min(start+v.synopsisHeight(), len(lines))
+	for _, line := range lines[start:end] {
+		out = append(out, lineStyle.Render(ansi.Truncate(line, innerWidth, "…")))
+	}
+
+	out = append(out, strings.Repeat("─", innerWidth))
+	footerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Width(innerWidth).
+		Align(lipgloss.Center)
+	out = append(out, footerStyle.Render("enter show in page • esc/S close"))
+
+	modalStyle := lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("212")).
+		Padding(0, 1).
+		Width(modalWidth)
+
+	return modalStyle.Render(strings.Join(out, "\n"))
+}
@@ -0,0 +1,224 @@
+package viewer
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/shadyabhi/mantee/man/parse"
+)
+
+// maxTableFlagWidth caps the flags column of the options table so one long
+// signature doesn't squeeze every summary
+const maxTableFlagWidth = 30
+
+// filteredTableRows returns the indices of the options shown in the options
+// table: all of them, or those whose flags or explanation contain the filter
+func (v Viewer) filteredTableRows() []int {
+	var rows []int
+	for i, s := range v.content.Sections {
+		if s.MatchesQuery(v.tableFilter) {
+			rows = append(rows, i)
+		}
+	}
+	return rows
+}
+
+// openOptionTable shows the options table with the cursor on the option
+// nearest the content cursor
+func (v *Viewer) openOptionTable() {
+	if !v.hasOptions() {
+		v.statusMsg = "No options in this page"
+		return
+	}
+	v.mode = modeTable
+	v.tableFilter = ""
+	v.tableFiltering = false
+	v.tableCursor = 0
+	currentLine := v.scrollOffset + v.contentCursor
+	for i, s := range v.content.Sections {
+		if s.StartLine > currentLine {
+			break
+		}
+		v.tableCursor = i
+	}
+	v.tableScrollOffset = 0
+	v.adjustTableScroll()
+}
+
+func (v Viewer) updateTable(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	rows := v.filteredTableRows()
+
+	if v.tableFiltering {
+		switch msg.String() {
+		case "ctrl+c":
+			v.quitting = true
+			return v, tea.Quit
+		case "esc":
+			v.setTableFilter("")
+			v.tableFiltering = false
+		case "enter":
+			v.tableFiltering = false
+		case "up", "down":
+			v.moveTableCursor(msg.String(), len(rows))
+		case "backspace":
+			if len(v.tableFilter) > 0 {
+				v.setTableFilter(v.tableFilter[:len(v.tableFilter)-1])
+			}
+		default:
+			if len(msg.String()) == 1 {
+				v.setTableFilter(v.tableFilter + msg.String())
+			}
+		}
+		return v, nil
+	}
+
+	switch msg.String() {
+	case "ctrl+c":
+		v.quitting = true
+		return v, tea.Quit
+
+	case "esc":
+		// First esc clears the filter, second closes the table
+		if v.tableFilter != "" {
+			v.setTableFilter("")
+		} else {
+			v.mode = modeNormal
+		}
+		return v, nil
+
+	case "T", "q":
+		v.mode = modeNormal
+		return v, nil
+
+	case "/":
+		v.tableFiltering = true
+		return v, nil
+
+	case "enter":
+		// Show the selected option in the page
+		if len(rows) > 0 {
+			v.jumpToLine(v.content.Sections[rows[v.tableCursor]].StartLine)
+			v.mode = modeNormal
+			v.focusPane = paneContent
+			v.syncSidebarToContent()
+		}
+		return v, nil
+
+	default:
+		v.moveTableCursor(msg.String(), len(rows))
+		return v, nil
+	}
+}
+
+// moveTableCursor handles the options table's navigation keys
+func (v *Viewer) moveTableCursor(key string, rowCount int) {
+	halfPage := max(v.tableHeight()/2, 1)
+	switch key {
+	case "up", "k":
+		v.tableCursor--
+	case "down", "j":
+		v.tableCursor++
+	case "ctrl+u", "pgup":
+		v.tableCursor -= halfPage
+	case "ctrl+d", "pgdown":
+		v.tableCursor += halfPage
+	case "home", "g":
+		v.tableCursor = 0
+	case "end", "G":
+		v.tableCursor = rowCount - 1
+	default:
+		return
+	}
+	v.tableCursor = min(max(v.tableCursor, 0), max(rowCount-1, 0))
+	v.adjustTableScroll()
+}
+
+// setTableFilter changes the table filter and returns the cursor to the top
+func (v *Viewer) setTableFilter(filter string) {
+	v.tableFilter = filter
+	v.tableCursor = 0
+	v.tableScrollOffset = 0
+}
+
+// tableHeight returns the number of option rows visible in the table
+func (v Viewer) tableHeight() int {
+	return v.viewportHeight() - 1 // -1 for the title
+}
+
+// adjustTableScroll ensures the table cursor is visible
+func (v *Viewer) adjustTableScroll() {
+	height := v.tableHeight()
+	if v.tableCursor < v.tableScrollOffset {
+		v.tableScrollOffset = v.tableCursor
+	} else if v.tableCursor >= v.tableScrollOffset+height {
+		v.tableScrollOffset = v.tableCursor - height + 1
+	}
+}
+
+// renderOptionTable renders every option as a flags | summary row in a
+// single full-width pane
+func (v Viewer) renderOptionTable() string {
+	var b strings.Builder
+	rows := v.filteredTableRows()
+	paneW := v.width - 2
+	innerW := paneW - 2 // Account for border
+
+	titleText := fmt.Sprintf("OPTIONS TABLE (%d)", len(v.content.Sections))
+	if v.tableFilter != "" {
+		titleText = fmt.Sprintf("OPTIONS TABLE (%d of %d matching %q)", len(rows), len(v.content.Sections), v.tableFilter)
+	}
+	b.WriteString(lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("255")).
+		Background(lipgloss.Color("62")).
+		Width(innerW).
+		Align(lipgloss.Center).
+		Render(titleText))
+	b.WriteString("\n")
+
+	flagW := 0
+	for _, i := range rows {
+		flagW = max(flagW, ansi.StringWidth(parse.ExtractOptionFlags(v.content.Sections[i].Option)))
+	}
+	flagW = min(flagW, maxTableFlagWidth, innerW/2)
+
+	flagStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	summaryStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Width(innerW)
+
+	height := v.tableHeight()
+	for i := 0; i < height; i++ {
+		idx := v.tableScrollOffset + i
+		switch {
+		case idx < len(rows):
+			s := v.content.Sections[rows[idx]]
+			flags := ansi.Truncate(parse.ExtractOptionFlags(s.Option), flagW, "…")
+			flags += strings.Repeat(" ", flagW-ansi.StringWidth(flags))
+			summary := ansi.Truncate(parse.SummarizeExplanation(s), max(innerW-flagW-4, 0), "…")
+			if idx == v.tableCursor {
+				b.WriteString(selectedStyle.Render("> " + flags + "  " + summary))
+			} else {
+				b.WriteString("  " + flagStyle.Render(flags) + "  " + summaryStyle.Render(summary))
+			}
+		case i == 0:
+			b.WriteString(helpStyle.Render("  No matching options"))
+		}
+		if i < height-1 {
+			b.WriteString("\n")
+		}
+	}
+
+	return lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("212")).
+		BorderLeft(true).
+		Width(paneW).
+		Render(b.String())
+}
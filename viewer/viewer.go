@@ -3,10 +3,16 @@ package viewer
 import (
 	"fmt"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/shadyabhi/mantee/history"
 	"github.com/shadyabhi/mantee/man/parse"
 	"github.com/shadyabhi/mantee/man/search"
 )
@@ -24,6 +30,10 @@ const (
 	modeSearch                          // Search/command input mode
 	modeSectionSelect                   // Section selector modal
 	modeHelp                            // Help/shortcuts modal
+	modeOutline                         // Outline (sections + options) modal
+	modeCommand                         // ":" command prompt
+	modeTable                           // Options table (flags | summary) in place of the panes
+	modeSynopsis                        // SYNOPSIS overlay
 )
 
 // searchType represents what field to search in
@@ -36,6 +46,37 @@ const (
 	searchDescription                   // Search description only
 )
 
+// searchTypeNames maps the config/flag names to search types
+var searchTypeNames = map[string]searchType{
+	"all":          searchAll,
+	"option":       searchOption,
+	"option-exact": searchOptionExact,
+	"description":  searchDescription,
+}
+
+// ValidateSearchType returns an error if name is not a known search type
+// ("all", "option", "option-exact", "description"). Empty means the default.
+func ValidateSearchType(name string) error {
+	if _, ok := searchTypeNames[name]; !ok && name != "" {
+		return fmt.Errorf("unknown search type %q (want all, option, option-exact, or description)", name)
+	}
+	return nil
+}
+
+// label returns a human-readable description of the search type
+func (t searchType) label() string {
+	switch t {
+	case searchOption:
+		return "Search options (partial)"
+	case searchOptionExact:
+		return "Search options (exact)"
+	case searchDescription:
+		return "Search descriptions"
+	default:
+		return "Search all content"
+	}
+}
+
 // focusPane represents which pane is currently focused
 type focusPane int
 
@@ -46,40 +87,248 @@ const (
 	paneCount                     // Total number of panes (must be last)
 )
 
+// paneNames maps the config names of panes to panes
+var paneNames = map[string]focusPane{
+	"options":  paneSidebar,
+	"content":  paneContent,
+	"sections": paneSections,
+}
+
+// defaultPaneOrder is the order tab cycles through the panes
+var defaultPaneOrder = []focusPane{paneSidebar, paneContent, paneSections}
+
+// ValidatePaneOrder returns an error unless names lists distinct panes
+// ("options", "content", "sections") including "content". Empty means the
+// default order.
+func ValidatePaneOrder(names []string) error {
+	seen := make(map[string]bool)
+	for _, name := range names {
+		if _, ok := paneNames[name]; !ok {
+			return fmt.Errorf("unknown pane %q (want options, content, or sections)", name)
+		}
+		if seen[name] {
+			return fmt.Errorf("pane %q listed twice", name)
+		}
+		seen[name] = true
+	}
+	if len(names) > 0 && !seen["content"] {
+		return fmt.Errorf("pane order must include content")
+	}
+	return nil
+}
+
+// parsePaneOrder returns the panes named by names, or the default order
+// when names is empty. Unknown names are skipped.
+func parsePaneOrder(names []string) []focusPane {
+	var order []focusPane
+	for _, name := range names {
+		if p, ok := paneNames[name]; ok {
+			order = append(order, p)
+		}
+	}
+	if len(order) == 0 {
+		return defaultPaneOrder
+	}
+	return order
+}
+
 // Viewer is the Bubble Tea model for the man page viewer
 type Viewer struct {
 	content             *parse.ManPageContent
+	sectionIndex        *parse.SectionIndex // Lowercased option text of content.Sections for searching
 	manPage             search.ManPage
+	fetchOpts           parse.FetchOptions // Options used to fetch content (for re-fetches)
 	mode                viewerMode
-	focusPane           focusPane  // Which pane is currently focused
-	sidebarCursor       int        // Current selection in the sidebar
-	sidebarScrollOffset int        // Scroll offset for sidebar
+	focusPane           focusPane // Which pane is currently focused
+	sidebarCursor       int       // Current selection in the sidebar
+	sidebarScrollOffset int       // Scroll offset for sidebar
 	searchInput         string
-	searchQuery         string     // Current active search query
-	searchType          searchType // What to search (all, option, description)
-	filteredIndices     []int      // Indices of sections matching the search (for option/desc search)
-	matchingLines       []int      // Line numbers matching the search (for full-text search)
-	currentMatch        int        // Current match index when navigating
-	scrollOffset        int        // Current scroll position
-	contentCursor       int        // Cursor position within content (relative to scrollOffset)
+	searchQuery         string            // Current active search query
+	searchType          searchType        // What to search (all, option, description)
+	defaultSearch       searchType        // Search type started by "/"
+	searchScope         *parse.ManSection // Man section the full-text search is confined to (nil for whole page)
+	filteredIndices     []int             // Indices of sections matching the search (for option/desc search)
+	matches             []searchMatch     // Occurrences of the query (for full-text search)
+	currentMatch        int               // Current match index when navigating
+	scrollOffset        int               // Current scroll position
+	contentCursor       int               // Cursor position within content (relative to scrollOffset)
+	hScrollOffset       int               // Horizontal scroll position (display columns skipped at the start of each line)
 	width               int
 	height              int
+	resizeSeq           int // Incremented per resize; matches the pending resizeSettledMsg
 	quitting            bool
+	searchPrompt        bool             // Whether ctrl+s may quit back to the search prompt
+	backToSearch        bool             // Whether the viewer quit for the search prompt
+	statusMsg           string           // Transient message shown in the status bar until the next key press
+	loading             string           // Page being fetched in the background, shown until it arrives
+	syncSidebar         bool             // Whether the sidebar cursor follows the content cursor
+	linkedScroll        bool             // Whether sidebar selection and content scroll move each other
+	sidebarExplain      bool             // Whether sidebar options show a summary of their explanation on a second line
+	wholeWord           bool             // Whether full-text search only matches whole words
+	smartCase           bool             // Whether a query with an uppercase letter matches case-sensitively
+	searchRegex         *regexp.Regexp   // Pattern of a regex ("!") full-text search, nil otherwise
+	searchPhrase        bool             // Whether the full-text query is one literal phrase, not terms (see searchTerms)
+	emphasis            bool             // Whether section headers and option flags are rendered bold
+	readerMode          bool             // Whether side panes are hidden and content is centered
+	centerContent       bool             // Whether a page much narrower than the content pane is centered in it
+	rawView             bool             // Whether man's output is shown verbatim in a single full-width pane
+	zoomed              bool             // Whether z re-formatted the page to the content pane's width
+	sectionRules        bool             // Whether man section headers are followed by a horizontal rule
+	unzoomedWidth       int              // fetchOpts.Width to restore when z is pressed again
+	fromStdin           bool             // Whether the current page was read from stdin rather than fetched
+	rawLines            []string         // Lines of content.RawContent while rawView is on
+	jumpList            []jumpPosition   // Positions before jumps, popped by ctrl+o
+	confirmQuit         bool             // Whether q asks for confirmation when there is state to lose
+	scrollStep          int              // Lines j/k move the content cursor
+	scrollAccel         bool             // Whether holding j/k grows the step (see accelStep)
+	accel               scrollAccelState // Recent j/k moves, for scroll acceleration
+	centerCursor        bool             // Whether the content scrolls to keep the cursor mid-viewport
+	scrollOff           int              // Context lines kept visible above and below the content cursor
+	clearSearchTop      bool             // Whether esc returns content and sidebar to the top when clearing a search
+	results             []search.ManPage // Search results the page was opened from, stepped through with ctrl+n/ctrl+p
+	resultIndex         int              // Index into results of the last result page shown
+	favorites           *history.Store   // Where the page is pinned as a favorite (nil disables pinning)
+	pinned              bool             // Whether the current page is a favorite
+	confirmingQuit      bool             // Whether the "Quit? (y/n)" prompt is showing
+	inline              bool             // Whether the viewer runs without the alt screen, so its last frame is kept
+	currentMatchColor   lipgloss.Color   // Background of the current search occurrence (and its arrow)
+	matchColor          lipgloss.Color   // Background of other matching lines
+	focusColor          lipgloss.Color   // Border color of the focused pane
+	focusBorder         lipgloss.Border  // Border style of the focused pane
+	matchBackgrounds    bool             // Whether other matching lines get the matchColor background
+	pendingQuickJump    bool             // Whether g was pressed and the next key picks a section to jump to
+	commandInput        string           // Text typed at the ":" prompt
+	commandHint         string           // Completion candidates shown after the ":" prompt
+	// Sections pane state
+	sectionsPaneScrollOffset int             // Scroll offset for the sections pane
+	sectionsPaneCursor       int             // Selected row of the sections pane
+	collapsedSections        map[string]bool // Man sections whose subsections the pane hides, by name
 	// Section selector state
-	sectionCursor       int // Current selection in section selector modal
-	sectionScrollOffset int // Scroll offset for section selector
+	sectionCursor       int    // Current selection in section selector modal
+	sectionScrollOffset int    // Scroll offset for section selector
+	sectionFilter       string // Filter text for the section selector
+	sectionFiltering    bool   // Whether the section selector filter input is active
+	sectionJumper       bool   // Whether the selector was opened by ctrl+g, so esc closes it outright
+	// Outline modal state
+	outlineFilter       string // Type-to-filter text in the outline modal
+	outlineCursor       int    // Current selection in the outline modal
+	outlineScrollOffset int    // Scroll offset for the outline modal
+	// Options table state
+	tableCursor       int    // Current row in the options table (index into its filtered rows)
+	tableScrollOffset int    // Scroll offset for the options table
+	tableFilter       string // Filter text narrowing the options table
+	tableFiltering    bool   // Whether the options table filter input is active
+	// SYNOPSIS overlay state
+	synopsisScrollOffset int // Scroll offset for a SYNOPSIS taller than the overlay
+
+	paneOrder   []focusPane // Panes tab cycles through, in order
+	enterAction enterAction // What enter does in the content pane
+}
+
+// Options configures a Viewer
+type Options struct {
+	Fetch          parse.FetchOptions // Options the content was fetched with, reused for re-fetches
+	DefaultSearch  string             // Search type started by "/" (see ValidateSearchType)
+	ConfirmQuit    bool               // Ask before q quits when there is state to lose
+	ScrollStep     int                // Lines j/k move (1 when zero)
+	ScrollAccel    bool               // Grow the j/k step while the key is held
+	CenterCursor   bool               // Keep the content cursor centered while moving
+	ScrollOff      int                // Context lines kept around the cursor (see DefaultScrollOff)
+	ClearSearchTop bool               // Return to the top when esc clears a search, instead of staying put
+	Results        []search.ManPage   // Search results the page was picked from (nil if none)
+	ResultIndex    int                // Index of the page within Results
+	Favorites      *history.Store     // Store F pins pages to (nil disables pinning)
+	Inline         bool               // Running without the alt screen; the last frame stays in scrollback
+	Search         string             // Full-text search to start with ("" for none)
+	SearchPhrase   bool               // Search Search as one literal phrase rather than space-separated terms
+	StartLine      int                // Line shown at the top on open, e.g. a grep hit
+	PaneOrder      []string           // Panes tab cycles through (see ValidatePaneOrder); nil for all
+	CenterContent  bool               // Center pages much narrower than the content pane
+	EnterAction    string             // What enter does in the content pane (see ValidateEnterAction)
+	SmartCase      bool               // Match case-sensitively when the query has an uppercase letter
+	FromStdin      bool               // The content was read from stdin, so it can't be re-fetched
+	SearchPrompt   bool               // ctrl+s may quit back to the search prompt (see BackToSearch)
+	SectionRules   bool               // Draw a rule after each man section header
+
+	// Search highlight colors, 0-255 or #rrggbb (see ValidateColor); empty
+	// for DefaultCurrentMatchColor and DefaultMatchColor
+	CurrentMatchColor string
+	MatchColor        string
+
+	// Focused pane border color (see ValidateColor) and style (see
+	// ValidateFocusBorder); empty for DefaultFocusColor and DefaultFocusBorder
+	FocusColor  string
+	FocusBorder string
 }
 
 // New creates a new Viewer for the given man page
-func New(page search.ManPage, content *parse.ManPageContent) Viewer {
-	return Viewer{
-		content:   content,
-		manPage:   page,
-		mode:      modeNormal,
-		focusPane: paneContent,
-		width:     80,
-		height:    24,
+func New(page search.ManPage, content *parse.ManPageContent, opts Options) Viewer {
+	v := Viewer{
+		content:      content,
+		sectionIndex: parse.NewSectionIndex(content.Sections),
+		manPage:      page,
+		fetchOpts:    opts.Fetch,
+		// Unknown names fall back to searchAll (the zero value)
+		defaultSearch:  searchTypeNames[opts.DefaultSearch],
+		emphasis:       true,
+		confirmQuit:    opts.ConfirmQuit,
+		scrollStep:     max(opts.ScrollStep, 1),
+		centerCursor:   opts.CenterCursor,
+		scrollOff:      max(opts.ScrollOff, 0),
+		clearSearchTop: opts.ClearSearchTop,
+		results:        opts.Results,
+		resultIndex:    opts.ResultIndex,
+		favorites:      opts.Favorites,
+		pinned:         opts.Favorites != nil && opts.Favorites.IsPinned(page),
+		inline:         opts.Inline,
+		mode:           modeNormal,
+		focusPane:      paneContent,
+		width:          80,
+		height:         24,
+	}
+	v.currentMatchColor = colorOr(opts.CurrentMatchColor, DefaultCurrentMatchColor)
+	v.matchColor = colorOr(opts.MatchColor, DefaultMatchColor)
+	v.focusColor = colorOr(opts.FocusColor, DefaultFocusColor)
+	v.focusBorder = focusBorders[DefaultFocusBorder]
+	if border, ok := focusBorders[opts.FocusBorder]; ok {
+		v.focusBorder = border
+	}
+	v.matchBackgrounds = true
+	v.scrollAccel = opts.ScrollAccel
+	v.fromStdin = opts.FromStdin
+	v.searchPrompt = opts.SearchPrompt
+	v.sectionRules = opts.SectionRules
+	v.paneOrder = parsePaneOrder(opts.PaneOrder)
+	v.centerContent = opts.CenterContent
+	v.smartCase = opts.SmartCase
+	// Unknown names fall back to enterFollow (the zero value)
+	v.enterAction = enterActionNames[opts.EnterAction]
+	if opts.Search != "" {
+		v.searchQuery = opts.Search
+		v.searchPhrase = opts.SearchPhrase
+		v.searchType = searchAll
+		v.matches = v.findMatches()
+	}
+	if opts.StartLine > 0 && len(content.Lines) > 0 {
+		// The height isn't known yet; relayout pulls the line up from the
+		// bottom once it is
+		line := min(opts.StartLine, len(content.Lines)-1)
+		v.scrollOffset = line
+		// Make the first match from there the current one for n/N
+		for i, m := range v.matches {
+			if m.line >= line {
+				v.currentMatch = i
+				break
+			}
+		}
 	}
+	return v
+}
+
+// BackToSearch reports whether the viewer quit with ctrl+s to return to the
+// search prompt rather than to exit
+func (v Viewer) BackToSearch() bool {
+	return v.backToSearch
 }
 
 // Init implements tea.Model
@@ -91,54 +340,196 @@ func (v Viewer) Init() tea.Cmd {
 func (v Viewer) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
-		v.width = msg.Width
-		v.height = msg.Height
-		return v, nil
+		return v.handleResize(msg)
+
+	case resizeSettledMsg:
+		if msg.seq != v.resizeSeq {
+			// Superseded by a later resize
+			return v, nil
+		}
+		return v.relayout()
 
 	case tea.MouseMsg:
 		// Handle mouse events
 		if msg.Type == tea.MouseLeft {
-			return v.handleMouseClick(msg)
+			model, cmd := v.handleMouseClick(msg)
+			return followSectionsPane(model), cmd
 		}
 		return v, nil
 
-	case tea.KeyMsg:
-		switch v.mode {
-		case modeNormal:
-			return v.updateNormal(msg)
-		case modeSearch:
-			return v.updateSearch(msg)
-		case modeSectionSelect:
-			return v.updateSectionSelect(msg)
-		case modeHelp:
-			return v.updateHelp(msg)
+	case externalExitMsg:
+		// Returned from an external pager/editor
+		if msg.cleanup != nil {
+			msg.cleanup()
+		}
+		if msg.err != nil {
+			v.statusMsg = fmt.Sprintf("External command failed: %v", msg.err)
+		}
+		return v, nil
+
+	case fetchStartedMsg:
+		v.loading = msg.page.Command()
+		return v, nil
+
+	case pageFetchedMsg:
+		return followSectionsPane(v.applyFetched(msg)), nil
+
+	case clipboardMsg:
+		if msg.err != nil {
+			v.statusMsg = fmt.Sprintf("Copy failed: %v", msg.err)
+		} else {
+			v.statusMsg = "Copied: " + msg.what
+		}
+		return v, nil
+
+	case locateResultMsg:
+		if msg.err != nil {
+			v.statusMsg = fmt.Sprintf("man -w failed: %v", msg.err)
+		} else if len(msg.paths) == 0 {
+			v.statusMsg = "man -w returned no path"
+		} else {
+			v.statusMsg = strings.Join(msg.paths, " • ")
 		}
+		return v, nil
+
+	case tea.KeyMsg:
+		model, cmd := v.updateKey(msg)
+		logKey(msg, v, model)
+		return followSectionsPane(model), cmd
+	}
+	return v, nil
+}
+
+// updateKey dispatches a key event to the handler for the current mode
+func (v Viewer) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v.statusMsg = ""
+	if v.confirmingQuit {
+		return v.updateQuitConfirm(msg)
+	}
+	if v.pendingQuickJump {
+		return v.updateQuickJump(msg)
+	}
+	switch v.mode {
+	case modeNormal:
+		return v.updateNormal(msg)
+	case modeSearch:
+		return v.updateSearch(msg)
+	case modeSectionSelect:
+		return v.updateSectionSelect(msg)
+	case modeHelp:
+		return v.updateHelp(msg)
+	case modeOutline:
+		return v.updateOutline(msg)
+	case modeCommand:
+		return v.updateCommand(msg)
+	case modeTable:
+		return v.updateTable(msg)
+	case modeSynopsis:
+		return v.updateSynopsis(msg)
+	}
+	return v, nil
+}
+
+// hasProgress reports whether quitting would lose an active search or jump
+// history
+func (v Viewer) hasProgress() bool {
+	return v.searchQuery != "" || len(v.jumpList) > 0
+}
+
+// updateQuitConfirm handles the "Quit? (y/n)" prompt: y quits (back to the
+// search prompt if ctrl+s asked), anything else cancels
+func (v Viewer) updateQuitConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v.confirmingQuit = false
+	switch msg.String() {
+	case "y", "Y":
+		v.quitting = true
+		return v, tea.Quit
+	case "ctrl+c":
+		v.backToSearch = false
+		v.quitting = true
+		return v, tea.Quit
 	}
+	v.backToSearch = false
 	return v, nil
 }
 
 func (v Viewer) updateNormal(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if v.rawView && rawUnavailableKeys[msg.String()] {
+		v.statusMsg = "Not available in raw view (R to return)"
+		return v, nil
+	}
+
 	// Global keys that work in any pane
 	switch msg.String() {
-	case "q", "ctrl+c":
+	case "ctrl+c":
+		v.quitting = true
+		return v, tea.Quit
+
+	case "q":
+		if v.confirmQuit && v.hasProgress() {
+			v.confirmingQuit = true
+			return v, nil
+		}
+		v.quitting = true
+		return v, tea.Quit
+
+	case "ctrl+s":
+		// Quit back to the search prompt to look for another page
+		if !v.searchPrompt {
+			v.statusMsg = "No search prompt to return to"
+			return v, nil
+		}
+		v.backToSearch = true
+		if v.confirmQuit && v.hasProgress() {
+			v.confirmingQuit = true
+			return v, nil
+		}
 		v.quitting = true
 		return v, tea.Quit
 
+	case "g":
+		// Leader for jumping straight to a common section (gn, gs, gd, go)
+		v.pendingQuickJump = true
+		return v, nil
+
 	case "tab":
-		// Cycle through panes forward
-		v.focusPane = (v.focusPane + 1) % paneCount
+		// Cycle through panes forward (only the content pane exists in reader
+		// mode and the raw view)
+		if !v.contentOnly() {
+			v.cyclePane(1)
+		}
 		return v, nil
 
 	case "shift+tab":
 		// Cycle through panes backward
-		v.focusPane = (v.focusPane + paneCount - 1) % paneCount
+		if !v.contentOnly() {
+			v.cyclePane(-1)
+		}
 		return v, nil
 
 	case "/":
-		// Enter search mode (search all)
+		// Enter search mode (configurable default, search all unless changed)
+		v.mode = modeSearch
+		v.searchInput = ""
+		v.searchType = v.defaultSearch
+		if v.rawView {
+			// Only full-text search works without parsing
+			v.searchType = searchAll
+		}
+		v.searchScope = nil
+		return v, nil
+
+	case "f":
+		// Enter search mode (full-text, limited to the current man section)
+		idx := v.currentManSectionIndex()
+		if idx == -1 {
+			return v, nil
+		}
+		scope := v.content.ManSections[idx]
 		v.mode = modeSearch
 		v.searchInput = ""
 		v.searchType = searchAll
+		v.searchScope = &scope
 		return v, nil
 
 	case "o":
@@ -146,6 +537,7 @@ func (v Viewer) updateNormal(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		v.mode = modeSearch
 		v.searchInput = ""
 		v.searchType = searchOption
+		v.searchScope = nil
 		return v, nil
 
 	case "O":
@@ -153,6 +545,7 @@ func (v Viewer) updateNormal(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		v.mode = modeSearch
 		v.searchInput = ""
 		v.searchType = searchOptionExact
+		v.searchScope = nil
 		return v, nil
 
 	case "d":
@@ -160,23 +553,22 @@ func (v Viewer) updateNormal(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		v.mode = modeSearch
 		v.searchInput = ""
 		v.searchType = searchDescription
+		v.searchScope = nil
 		return v, nil
 
 	case "esc":
-		// Clear search and reset sidebar filter
-		v.searchQuery = ""
-		v.filteredIndices = nil
-		v.matchingLines = nil
-		v.currentMatch = 0
-		v.sidebarCursor = 0
-		v.sidebarScrollOffset = 0
+		v.clearSearch()
 		return v, nil
 
 	case "n":
 		// Next match (works from any pane, focuses content)
 		matchCount := v.totalMatches()
 		if matchCount > 0 {
+			prev := v.currentMatch
 			v.currentMatch = (v.currentMatch + 1) % matchCount
+			if v.currentMatch <= prev {
+				v.statusMsg = "search hit BOTTOM, continuing at TOP"
+			}
 			v.scrollToCurrentMatch()
 			v.focusPane = paneContent
 		}
@@ -189,6 +581,7 @@ func (v Viewer) updateNormal(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			v.currentMatch--
 			if v.currentMatch < 0 {
 				v.currentMatch = matchCount - 1
+				v.statusMsg = "search hit TOP, continuing at BOTTOM"
 			}
 			v.scrollToCurrentMatch()
 			v.focusPane = paneContent
@@ -196,6 +589,10 @@ func (v Viewer) updateNormal(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return v, nil
 
 	case "G":
+		if v.rawView {
+			// No sections to select; go to the bottom instead
+			break
+		}
 		// Open section selector modal
 		if len(v.content.ManSections) > 0 {
 			v.mode = modeSectionSelect
@@ -204,10 +601,151 @@ func (v Viewer) updateNormal(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return v, nil
 
+	case "ctrl+g":
+		// Open the section selector ready to type a fuzzy filter
+		if len(v.content.ManSections) == 0 {
+			v.statusMsg = "No sections in this page"
+			return v, nil
+		}
+		v.clearSectionFilter()
+		v.mode = modeSectionSelect
+		v.sectionFiltering = true
+		v.sectionJumper = true
+		return v, nil
+
+	case "ctrl+t":
+		// Re-run the search as the next search type
+		v.cycleSearchType()
+		return v, nil
+
+	case "ctrl+r":
+		// Re-fetch the page, e.g. after editing its source
+		return v, v.reload()
+
+	case "ctrl+o":
+		// Return to the position before the last jump
+		if v.jumpBack() {
+			v.focusPane = paneContent
+		} else {
+			v.statusMsg = "Jump list is empty"
+		}
+		return v, nil
+
+	case "ctrl+n":
+		// Open the next page from the search results
+		cmd := v.stepResult(1)
+		return v, cmd
+
+	case "ctrl+p":
+		// Open the previous page from the search results
+		cmd := v.stepResult(-1)
+		return v, cmd
+
+	case "t":
+		// Open outline modal (sections with nested options)
+		return v.openOutline(), nil
+
 	case "?":
 		// Open help modal
 		v.mode = modeHelp
 		return v, nil
+
+	case "p":
+		// Open raw content in $PAGER
+		return v.openInPager()
+
+	case "e":
+		// Open raw content in $EDITOR
+		return v.openInEditor()
+
+	case "c":
+		// Copy the man command for this page
+		command := v.manPage.Command()
+		return v, copyToClipboard(command, command)
+
+	case "y":
+		// Copy a reference to the current man section
+		cmd := v.copySectionRef()
+		return v, cmd
+
+	case "Y":
+		// Copy the example code block under the cursor
+		cmd := v.copyExample()
+		return v, cmd
+
+	case "C":
+		// Copy all options as a cheatsheet table
+		return v, v.copyOptionTable()
+
+	case "w":
+		// Show the source file path(s) in the status bar
+		return v, v.locatePage()
+
+	case "F":
+		// Pin or unpin this page as a favorite
+		v.togglePin()
+		return v, nil
+
+	case "x":
+		// Re-fetch with the other col mode (tab expansion keeps tables aligned)
+		return v, v.setExpandTabs(v.fetchOpts.Col != parse.ColExpandTabs)
+
+	case "z":
+		// Re-format the page to fill the content pane, or back
+		cmd := v.toggleZoom()
+		return v, cmd
+
+	case "s":
+		// Toggle sidebar following the content cursor
+		v.setSyncSidebar(!v.syncSidebar)
+		return v, nil
+
+	case "L":
+		// Toggle linked scroll (sidebar and content move each other)
+		v.setLinkedScroll(!v.linkedScroll)
+		return v, nil
+
+	case "i":
+		// Toggle option summaries under the sidebar options
+		v.setSidebarExplain(!v.sidebarExplain)
+		return v, nil
+
+	case "b":
+		// Toggle synthetic bold for section headers and option flags
+		v.setEmphasis(!v.emphasis)
+		return v, nil
+
+	case "B":
+		// Toggle the background of matching lines other than the current one
+		v.setMatchBackgrounds(!v.matchBackgrounds)
+		return v, nil
+
+	case "r":
+		// Toggle reader mode (content only, centered)
+		v.setReaderMode(!v.readerMode)
+		return v, nil
+
+	case "R":
+		// Toggle the raw view (man's output verbatim, full width)
+		v.setRawView(!v.rawView)
+		return v, nil
+
+	case "S":
+		// Show the SYNOPSIS in an overlay
+		v.openSynopsis()
+		return v, nil
+
+	case "T":
+		// Show the options as a flags | summary table
+		v.openOptionTable()
+		return v, nil
+
+	case ":":
+		// Open the command prompt
+		v.mode = modeCommand
+		v.commandInput = ""
+		v.commandHint = ""
+		return v, nil
 	}
 
 	// Pane-specific keys
@@ -217,7 +755,17 @@ func (v Viewer) updateNormal(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case paneSections:
 		return v.updateSections(msg)
 	default:
-		return v.updateContent(msg)
+		model, cmd := v.updateContent(msg)
+		updated := model.(Viewer)
+		switch {
+		case updated.rawView:
+			// Raw line numbers don't match the sidebar's options
+		case updated.linkedScroll:
+			updated.linkSidebarToContent()
+		case updated.syncSidebar:
+			updated.syncSidebarToContent()
+		}
+		return updated, cmd
 	}
 }
 
@@ -232,6 +780,8 @@ func (v Viewer) updateSidebar(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if v.sidebarCursor > 0 {
 			v.sidebarCursor--
 			v.adjustSidebarScroll()
+			v.linkContentToSidebar()
+			v.syncMatchToSidebar()
 		}
 		return v, nil
 
@@ -239,18 +789,26 @@ func (v Viewer) updateSidebar(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if v.sidebarCursor < len(displayedIndices)-1 {
 			v.sidebarCursor++
 			v.adjustSidebarScroll()
+			v.linkContentToSidebar()
+			v.syncMatchToSidebar()
 		}
 		return v, nil
 
+	case "J":
+		// Next entry, showing its match in the content; focus stays here
+		v.stepSidebarMatch(1)
+		return v, nil
+
+	case "K":
+		// Previous entry, showing its match in the content
+		v.stepSidebarMatch(-1)
+		return v, nil
+
 	case "enter", "right", "l":
 		// Jump to the selected section in content
 		sectionIdx := displayedIndices[v.sidebarCursor]
 		section := v.content.Sections[sectionIdx]
-		v.scrollOffset = section.StartLine
-		if v.scrollOffset < 0 {
-			v.scrollOffset = 0
-		}
-		v.contentCursor = 0
+		v.jumpToLine(section.StartLine)
 		// Switch to content pane after jumping
 		v.focusPane = paneContent
 		return v, nil
@@ -258,11 +816,13 @@ func (v Viewer) updateSidebar(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "home":
 		v.sidebarCursor = 0
 		v.sidebarScrollOffset = 0
+		v.linkContentToSidebar()
 		return v, nil
 
 	case "G":
 		v.sidebarCursor = len(displayedIndices) - 1
 		v.adjustSidebarScroll()
+		v.linkContentToSidebar()
 		return v, nil
 	}
 	return v, nil
@@ -270,48 +830,187 @@ func (v Viewer) updateSidebar(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 // adjustSidebarScroll ensures the sidebar cursor is visible
 func (v *Viewer) adjustSidebarScroll() {
-	vpHeight := v.viewportHeight()
+	visible := v.sidebarVisibleEntries()
 	if v.sidebarCursor < v.sidebarScrollOffset {
 		v.sidebarScrollOffset = v.sidebarCursor
-	} else if v.sidebarCursor >= v.sidebarScrollOffset+vpHeight {
-		v.sidebarScrollOffset = v.sidebarCursor - vpHeight + 1
+	} else if v.sidebarCursor >= v.sidebarScrollOffset+visible {
+		v.sidebarScrollOffset = v.sidebarCursor - visible + 1
+	}
+}
+
+// sidebarRowsPerEntry returns how many rows each sidebar option takes: two
+// when its summary is shown underneath
+func (v Viewer) sidebarRowsPerEntry() int {
+	if v.sidebarExplain {
+		return 2
 	}
+	return 1
+}
+
+// sidebarVisibleEntries returns how many options fit in the sidebar
+func (v Viewer) sidebarVisibleEntries() int {
+	return max((v.viewportHeight()-1)/v.sidebarRowsPerEntry(), 1) // -1 for title
+}
+
+// sidebarMatch returns the index of the search match in the selected sidebar
+// entry: the entry itself for option and description searches, or the first
+// occurrence within it for full-text search. It returns -1 if there is none.
+func (v Viewer) sidebarMatch() int {
+	displayed := v.getDisplayedSectionIndices()
+	if v.sidebarCursor >= len(displayed) {
+		return -1
+	}
+	if len(v.matches) > 0 {
+		section := v.content.Sections[displayed[v.sidebarCursor]]
+		for i, m := range v.matches {
+			if m.line >= section.StartLine && m.line <= section.EndLine {
+				return i
+			}
+		}
+		return -1
+	}
+	if len(v.filteredIndices) > 0 {
+		// The sidebar lists exactly the filtered options
+		return v.sidebarCursor
+	}
+	return -1
+}
+
+// syncMatchToSidebar makes the selected entry's match the current one, so
+// n/N continue from the sidebar selection rather than from an older match
+func (v *Viewer) syncMatchToSidebar() {
+	if i := v.sidebarMatch(); i >= 0 {
+		v.currentMatch = i
+	}
+}
+
+// stepSidebarMatch moves the sidebar cursor delta entries, wrapping around,
+// and centers the content on the new entry's match (or, with no search, on
+// the option itself) while focus stays in the sidebar
+func (v *Viewer) stepSidebarMatch(delta int) {
+	displayed := v.getDisplayedSectionIndices()
+	if len(displayed) == 0 {
+		return
+	}
+	v.sidebarCursor = (v.sidebarCursor + delta + len(displayed)) % len(displayed)
+	v.adjustSidebarScroll()
+
+	if i := v.sidebarMatch(); i >= 0 {
+		v.currentMatch = i
+		v.scrollToCurrentMatch()
+	} else {
+		v.pushJump()
+		line := v.content.Sections[displayed[v.sidebarCursor]].StartLine
+		maxScroll := max(len(v.lines())-v.viewportHeight(), 0)
+		v.scrollOffset = min(max(line-v.viewportHeight()/2, 0), maxScroll)
+	}
+	v.statusMsg = fmt.Sprintf("Entry %d/%d", v.sidebarCursor+1, len(displayed))
+}
+
+// clearSearch clears the search and the sidebar filter. The content and the
+// sidebar either both stay where they are, with the sidebar moved to the
+// option nearest the content cursor, or (with clearSearchTop) both return
+// to the top.
+func (v *Viewer) clearSearch() {
+	v.searchQuery = ""
+	v.searchRegex = nil
+	v.searchPhrase = false
+	v.wholeWord = false
+	v.searchScope = nil
+	v.filteredIndices = nil
+	v.matches = nil
+	v.currentMatch = 0
+
+	if v.clearSearchTop {
+		v.scrollOffset = 0
+		v.contentCursor = 0
+		v.hScrollOffset = 0
+		v.sidebarCursor = 0
+		v.sidebarScrollOffset = 0
+		return
+	}
+	v.sidebarCursor = v.nearestSection(v.scrollOffset + v.contentCursor)
+	v.adjustSidebarScroll()
+}
+
+// nearestSection returns the index of the option section containing line,
+// or else the last one starting before it (the first if none does)
+func (v Viewer) nearestSection(line int) int {
+	nearest := 0
+	for i, section := range v.content.Sections {
+		if section.StartLine > line {
+			break
+		}
+		nearest = i
+	}
+	return nearest
+}
+
+// syncSidebarToContent moves the sidebar cursor to the option section that
+// contains the current content line, if that section is displayed
+func (v *Viewer) syncSidebarToContent() {
+	currentLine := v.scrollOffset + v.contentCursor
+	for i, sectionIdx := range v.getDisplayedSectionIndices() {
+		section := v.content.Sections[sectionIdx]
+		if currentLine >= section.StartLine && currentLine <= section.EndLine {
+			v.sidebarCursor = i
+			v.adjustSidebarScroll()
+			return
+		}
+	}
+}
+
+// linkSidebarToContent selects the displayed option nearest the content
+// cursor: the one containing it, or else the last one starting before it.
+// Unlike syncSidebarToContent the selection also moves between options.
+func (v *Viewer) linkSidebarToContent() {
+	currentLine := v.scrollOffset + v.contentCursor
+	displayed := v.getDisplayedSectionIndices()
+	if len(displayed) == 0 {
+		return
+	}
+	cursor := 0
+	for i, sectionIdx := range displayed {
+		if v.content.Sections[sectionIdx].StartLine > currentLine {
+			break
+		}
+		cursor = i
+	}
+	v.sidebarCursor = cursor
+	v.adjustSidebarScroll()
+}
+
+// linkContentToSidebar scrolls the content to the selected option's first
+// line when linked scroll is on. Focus stays in the sidebar and the jump list
+// is left alone, since this follows every sidebar move.
+func (v *Viewer) linkContentToSidebar() {
+	if !v.linkedScroll {
+		return
+	}
+	displayed := v.getDisplayedSectionIndices()
+	if v.sidebarCursor >= len(displayed) {
+		return
+	}
+	line := v.content.Sections[displayed[v.sidebarCursor]].StartLine
+	maxScroll := max(len(v.lines())-v.viewportHeight(), 0)
+	v.scrollOffset = min(line, maxScroll)
+	v.contentCursor = line - v.scrollOffset
 }
 
 func (v Viewer) updateContent(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	vpHeight := v.viewportHeight()
-	maxLine := len(v.content.Lines) - 1
+	maxLine := len(v.lines()) - 1
 	if maxLine < 0 {
 		maxLine = 0
 	}
 
 	switch msg.String() {
 	case "up", "k":
-		// Move cursor up
-		currentLine := v.scrollOffset + v.contentCursor
-		if currentLine > 0 {
-			if v.contentCursor > 0 {
-				// Cursor can move up within viewport
-				v.contentCursor--
-			} else {
-				// Cursor at top of viewport, scroll up
-				v.scrollOffset--
-			}
-		}
+		v.moveCursor(v.accelStep(-1))
 		return v, nil
 
 	case "down", "j":
-		// Move cursor down
-		currentLine := v.scrollOffset + v.contentCursor
-		if currentLine < maxLine {
-			if v.contentCursor < vpHeight-1 {
-				// Cursor can move down within viewport
-				v.contentCursor++
-			} else {
-				// Cursor at bottom of viewport, scroll down
-				v.scrollOffset++
-			}
-		}
+		v.moveCursor(v.accelStep(1))
 		return v, nil
 
 	case "pgup", "ctrl+u":
@@ -338,7 +1037,7 @@ func (v Viewer) updateContent(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			newLine = maxLine
 		}
 		// Adjust scroll and cursor
-		maxScroll := len(v.content.Lines) - vpHeight
+		maxScroll := len(v.lines()) - vpHeight
 		if maxScroll < 0 {
 			maxScroll = 0
 		}
@@ -359,7 +1058,7 @@ func (v Viewer) updateContent(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return v, nil
 
 	case "G":
-		maxScroll := len(v.content.Lines) - vpHeight
+		maxScroll := len(v.lines()) - vpHeight
 		if maxScroll < 0 {
 			maxScroll = 0
 		}
@@ -370,57 +1069,180 @@ func (v Viewer) updateContent(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return v, nil
 
+	case "}", "]":
+		// Jump to the next man section header
+		currentLine := v.scrollOffset + v.contentCursor
+		for _, section := range v.content.ManSections {
+			if section.StartLine > currentLine {
+				v.jumpToLine(section.StartLine)
+				break
+			}
+		}
+		return v, nil
+
+	case "{", "[":
+		// Jump to the previous man section header
+		currentLine := v.scrollOffset + v.contentCursor
+		sections := v.content.ManSections
+		for i := len(sections) - 1; i >= 0; i-- {
+			if sections[i].StartLine < currentLine {
+				v.jumpToLine(sections[i].StartLine)
+				break
+			}
+		}
+		return v, nil
+
 	case "left", "h":
-		// Switch to sidebar
-		v.focusPane = paneSidebar
+		// Switch to sidebar (which can't take focus without options)
+		if !v.contentOnly() && v.hasOptions() {
+			v.focusPane = paneSidebar
+		}
 		return v, nil
 
 	case "right", "l":
 		// Switch to sections pane
-		v.focusPane = paneSections
+		if !v.contentOnly() {
+			v.focusPane = paneSections
+		}
+		return v, nil
+
+	case "shift+left":
+		v.hScrollOffset = max(v.hScrollOffset-hScrollStep, 0)
+		return v, nil
+
+	case "shift+right":
+		v.hScrollOffset = min(v.hScrollOffset+hScrollStep, v.maxHScroll())
+		return v, nil
+
+	case "0":
+		v.hScrollOffset = 0
+		return v, nil
+
+	case "$":
+		v.hScrollOffset = v.maxHScroll()
 		return v, nil
+
+	case "enter":
+		// Follow a reference, copy the line, or nothing (enter_action)
+		cmd := v.contentEnter()
+		return v, cmd
 	}
 	return v, nil
 }
 
+// DefaultScrollOff is the number of context lines kept above and below the
+// content cursor when no scrolloff is configured
+const DefaultScrollOff = 3
+
+// moveCursor moves the content cursor by delta lines, stopping at the first
+// and last line. The viewport scrolls once the cursor comes within scrollOff
+// lines of its edge, like vim's 'scrolloff'; with centerCursor it scrolls to
+// keep the cursor in the middle. Either way the viewport never scrolls past
+// the top or bottom of the page, so the cursor can still reach both ends.
+func (v *Viewer) moveCursor(delta int) {
+	vpHeight := v.viewportHeight()
+	maxLine := max(len(v.lines())-1, 0)
+	maxScroll := max(len(v.lines())-vpHeight, 0)
+	line := min(max(v.scrollOffset+v.contentCursor+delta, 0), maxLine)
+
+	if v.centerCursor {
+		v.scrollOffset = min(max(line-vpHeight/2, 0), maxScroll)
+	} else {
+		// A margin over half the viewport would pin the cursor; cap it
+		margin := min(v.scrollOff, (vpHeight-1)/2)
+		switch {
+		case line < v.scrollOffset+margin:
+			v.scrollOffset = max(line-margin, 0)
+		case line > v.scrollOffset+vpHeight-1-margin:
+			// Don't pull back a viewport that a jump already left past maxScroll
+			v.scrollOffset = min(line-vpHeight+1+margin, max(maxScroll, v.scrollOffset))
+		}
+	}
+	v.contentCursor = line - v.scrollOffset
+}
+
+// hScrollStep is how many columns shift+left/right scroll horizontally
+const hScrollStep = 8
+
+// visibleContentWidth returns how many characters of a line fit in the
+// content pane (excluding the border and the arrow indicator)
+func (v Viewer) visibleContentWidth() int {
+	return v.contentWidth() - 4
+}
+
+// maxHScroll returns the horizontal offset at which the longest line's end
+// is visible
+func (v Viewer) maxHScroll() int {
+	longest := 0
+	for _, line := range v.lines() {
+		longest = max(longest, ansi.StringWidth(line))
+	}
+	return max(longest-v.visibleContentWidth(), 0)
+}
+
+// hScrollStart returns the byte offset in line where its visible part
+// starts once hScrollOffset columns are scrolled off. Byte ranges into the
+// line (search matches, styled runs) shift by this much into the visible
+// part.
+func (v Viewer) hScrollStart(line string) int {
+	return len(line) - len(ansi.TruncateLeft(line, v.hScrollOffset, ""))
+}
+
+// columnOffset returns the byte offset in line of the character drawn at
+// display column col of the visible part, or -1 for a column left of it.
+// A click lands on a column, while page references and option flags are
+// found by byte offset; the two differ past any multi-byte or wide
+// character.
+func (v Viewer) columnOffset(line string, col int) int {
+	if col < 0 {
+		return -1
+	}
+	start := v.hScrollStart(line)
+	return start + len(ansi.Truncate(line[start:], col, ""))
+}
+
 // updateSections handles key events for the sections pane (right sidebar)
 func (v Viewer) updateSections(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	sections := v.content.ManSections
-	if len(sections) == 0 {
+	// Rows skip the subsections of collapsed sections
+	rows := v.sectionsPaneRows()
+	if len(rows) == 0 {
 		return v, nil
 	}
 
-	// Use sectionCursor for navigation in sections pane
 	switch msg.String() {
 	case "up", "k":
-		if v.sectionCursor > 0 {
-			v.sectionCursor--
+		if v.sectionsPaneCursor > 0 {
+			v.sectionsPaneCursor--
 		}
+		v.adjustSectionsPaneScroll()
 		return v, nil
 
 	case "down", "j":
-		if v.sectionCursor < len(sections)-1 {
-			v.sectionCursor++
+		if v.sectionsPaneCursor < len(rows)-1 {
+			v.sectionsPaneCursor++
 		}
+		v.adjustSectionsPaneScroll()
 		return v, nil
 
 	case "enter", "l":
-		// Jump to selected section
-		section := sections[v.sectionCursor]
-		v.scrollOffset = section.StartLine
-		if v.scrollOffset < 0 {
-			v.scrollOffset = 0
-		}
-		v.contentCursor = 0
+		// Jump to selected section or subsection
+		section := v.rowSection(rows[v.sectionsPaneCursor])
+		v.jumpToLine(section.StartLine)
 		v.focusPane = paneContent
 		return v, nil
 
+	case " ":
+		v.toggleSectionCollapsed()
+		return v, nil
+
 	case "home":
-		v.sectionCursor = 0
+		v.sectionsPaneCursor = 0
+		v.adjustSectionsPaneScroll()
 		return v, nil
 
 	case "G":
-		v.sectionCursor = len(sections) - 1
+		v.sectionsPaneCursor = len(rows) - 1
+		v.adjustSectionsPaneScroll()
 		return v, nil
 
 	case "left", "h":
@@ -445,27 +1267,8 @@ func (v Viewer) updateSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return v, nil
 
 	case "enter":
-		// Execute search
-		v.searchQuery = v.searchInput
-		v.currentMatch = 0
-		// Reset sidebar cursor and scroll for filtered view
-		v.sidebarCursor = 0
-		v.sidebarScrollOffset = 0
-		if v.searchType == searchAll {
-			// Full-text search across all lines
-			v.matchingLines = v.findMatchingLines()
-			v.filteredIndices = nil
-			if len(v.matchingLines) > 0 {
-				v.scrollToCurrentMatch()
-			}
-		} else {
-			// Section-based search (option or description)
-			v.filteredIndices = v.findMatchingSections()
-			v.matchingLines = nil
-			if len(v.filteredIndices) > 0 {
-				v.scrollToCurrentMatch()
-			}
-		}
+		// Execute search, switching type by any prefix (o:, O:, d:, /, !, w:)
+		v.submitSearch(v.searchInput)
 		v.mode = modeNormal
 		v.focusPane = paneContent // Keep focus on content pane after search
 		return v, nil
@@ -485,21 +1288,82 @@ func (v Viewer) updateSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	}
 }
 
+// runSearch searches for query with the current search type and scrolls to
+// the first match
+func (v *Viewer) runSearch(query string) {
+	v.searchQuery = query
+	v.currentMatch = 0
+	// Reset sidebar cursor and scroll for filtered view
+	v.sidebarCursor = 0
+	v.sidebarScrollOffset = 0
+	if v.searchType == searchAll {
+		// Full-text search across all lines
+		v.matches = v.findMatches()
+		v.filteredIndices = nil
+		if len(v.matches) > 0 {
+			v.scrollToCurrentMatch()
+		}
+	} else {
+		// Section-based search (option or description)
+		v.filteredIndices = v.findMatchingSections()
+		v.matches = nil
+		if len(v.filteredIndices) > 0 {
+			v.scrollToCurrentMatch()
+		}
+	}
+}
+
+// searchTypeCycle is the order ctrl+t steps the active search through
+var searchTypeCycle = []searchType{searchAll, searchOption, searchOptionExact, searchDescription}
+
+// cycleSearchType re-runs the active query as the next search type, so the
+// same term can be compared across full text, options and descriptions
+func (v *Viewer) cycleSearchType() {
+	if v.searchQuery == "" {
+		v.statusMsg = "No search to switch (start one with /)"
+		return
+	}
+	i := slices.Index(searchTypeCycle, v.searchType)
+	v.searchType = searchTypeCycle[(i+1)%len(searchTypeCycle)]
+	// A section scope only applies to full-text search, and a regex is
+	// re-run as plain text
+	v.searchScope = nil
+	v.searchRegex = nil
+	v.runSearch(v.searchQuery)
+	v.focusPane = paneContent
+	v.statusMsg = v.searchType.label()
+}
+
 func (v Viewer) updateSectionSelect(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	sections := v.content.ManSections
-	if len(sections) == 0 {
+	if len(v.content.ManSections) == 0 {
 		v.mode = modeNormal
 		return v, nil
 	}
 
+	// While the filter input is active, typing narrows the list
+	if v.sectionFiltering {
+		return v.updateSectionFilter(msg)
+	}
+
+	indices := v.filteredManSectionIndices()
+
 	switch msg.String() {
 	case "ctrl+c":
 		v.quitting = true
 		return v, tea.Quit
 
-	case "esc", "g":
-		// Close section selector
-		v.mode = modeNormal
+	case "esc":
+		// First clear an active filter, then close section selector
+		if v.sectionFilter != "" {
+			v.clearSectionFilter()
+			return v, nil
+		}
+		v.closeSectionSelect()
+		return v, nil
+
+	case "/":
+		// Start typing a filter
+		v.sectionFiltering = true
 		return v, nil
 
 	case "up", "k":
@@ -510,37 +1374,125 @@ func (v Viewer) updateSectionSelect(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return v, nil
 
 	case "down", "j":
-		if v.sectionCursor < len(sections)-1 {
+		if v.sectionCursor < len(indices)-1 {
 			v.sectionCursor++
 			v.adjustSectionScroll()
 		}
 		return v, nil
 
 	case "enter", "l":
-		// Jump to selected section
-		section := sections[v.sectionCursor]
-		v.scrollOffset = section.StartLine
-		if v.scrollOffset < 0 {
-			v.scrollOffset = 0
-		}
-		v.contentCursor = 0
-		v.mode = modeNormal
-		v.focusPane = paneContent
-		return v, nil
+		return v.jumpToSelectedSection(), nil
 
-	case "home":
+	case "home", "g":
 		v.sectionCursor = 0
 		v.sectionScrollOffset = 0
 		return v, nil
 
 	case "end", "G":
-		v.sectionCursor = len(sections) - 1
-		v.adjustSectionScroll()
+		if len(indices) > 0 {
+			v.sectionCursor = len(indices) - 1
+			v.adjustSectionScroll()
+		}
 		return v, nil
 	}
 	return v, nil
 }
 
+// updateSectionFilter handles keys while typing a filter in the section selector
+func (v Viewer) updateSectionFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	indices := v.filteredManSectionIndices()
+
+	switch msg.String() {
+	case "ctrl+c":
+		v.quitting = true
+		return v, tea.Quit
+
+	case "esc":
+		if v.sectionJumper {
+			v.closeSectionSelect()
+			return v, nil
+		}
+		v.clearSectionFilter()
+		return v, nil
+
+	case "enter":
+		return v.jumpToSelectedSection(), nil
+
+	case "up", "ctrl+p":
+		if v.sectionCursor > 0 {
+			v.sectionCursor--
+			v.adjustSectionScroll()
+		}
+		return v, nil
+
+	case "down", "ctrl+n":
+		if v.sectionCursor < len(indices)-1 {
+			v.sectionCursor++
+			v.adjustSectionScroll()
+		}
+		return v, nil
+
+	case "backspace":
+		if len(v.sectionFilter) > 0 {
+			v.sectionFilter = v.sectionFilter[:len(v.sectionFilter)-1]
+			v.sectionCursor = 0
+			v.sectionScrollOffset = 0
+		}
+		return v, nil
+
+	default:
+		if len(msg.String()) == 1 {
+			v.sectionFilter += msg.String()
+			v.sectionCursor = 0
+			v.sectionScrollOffset = 0
+		}
+		return v, nil
+	}
+}
+
+// filteredManSectionIndices returns indices of ManSections matching the
+// section selector filter (all sections when the filter is empty)
+func (v Viewer) filteredManSectionIndices() []int {
+	var indices []int
+	for i, section := range v.content.ManSections {
+		if v.sectionFilter == "" || fuzzyMatch(v.sectionFilter, section.Name) {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// clearSectionFilter resets the section selector filter and cursor
+func (v *Viewer) clearSectionFilter() {
+	v.sectionFilter = ""
+	v.sectionFiltering = false
+	v.sectionCursor = 0
+	v.sectionScrollOffset = 0
+}
+
+// closeSectionSelect closes the section selector, discarding any filter
+func (v *Viewer) closeSectionSelect() {
+	v.clearSectionFilter()
+	v.sectionJumper = false
+	v.mode = modeNormal
+}
+
+// jumpToSelectedSection jumps to the section under the modal cursor and closes the modal
+func (v Viewer) jumpToSelectedSection() Viewer {
+	indices := v.filteredManSectionIndices()
+	if len(indices) == 0 {
+		return v
+	}
+	sectionIdx := indices[v.sectionCursor]
+	section := v.content.ManSections[sectionIdx]
+	v.jumpToLine(section.StartLine)
+	v.closeSectionSelect()
+	// Keep the sections pane cursor on the chosen section
+	v.sectionsPaneCursor = v.sectionsPaneRow(sectionIdx, -1)
+	v.focusPane = paneContent
+	return v
+}
+
 func (v Viewer) updateHelp(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "ctrl+c":
@@ -579,55 +1531,195 @@ func (v Viewer) sectionModalHeight() int {
 	return maxHeight
 }
 
+// caseSensitive reports whether the active search matches case-sensitively:
+// with smartcase on, when the query has an uppercase letter
+func (v Viewer) caseSensitive() bool {
+	return v.smartCase && parse.CaseSensitive(v.searchQuery)
+}
+
+// foldCase lowercases s for matching against the active search, unless it
+// matches case-sensitively
+func (v Viewer) foldCase(s string) string {
+	if v.caseSensitive() {
+		return s
+	}
+	return strings.ToLower(s)
+}
+
 // findMatchingSections returns indices of sections matching the current search query
 func (v Viewer) findMatchingSections() []int {
-	var indices []int
-	for i, section := range v.content.Sections {
-		var matches bool
-		switch v.searchType {
-		case searchOption:
-			matches = section.MatchesOption(v.searchQuery)
-		case searchOptionExact:
-			matches = section.MatchesOptionExact(v.searchQuery)
-		case searchDescription:
-			matches = section.MatchesDescription(v.searchQuery)
-		default:
-			matches = section.MatchesQuery(v.searchQuery)
-		}
-		if matches {
-			indices = append(indices, i)
-		}
+	switch v.searchType {
+	case searchOption:
+		return v.sectionIndex.MatchOption(v.searchQuery, v.caseSensitive())
+	case searchOptionExact:
+		// Fall back to grouped short flags ("-abc") when no flag matches exactly
+		if indices := v.sectionIndex.MatchOptionExact(v.searchQuery); len(indices) > 0 {
+			return indices
+		}
+		return v.sectionIndex.MatchOptionGrouped(v.searchQuery)
+	case searchDescription:
+		return v.sectionIndex.MatchDescription(v.searchQuery, v.caseSensitive())
+	default:
+		return v.sectionIndex.MatchQuery(v.searchQuery, v.caseSensitive())
 	}
-	return indices
+}
+
+// searchMatch is a single occurrence of a full-text search term
+type searchMatch struct {
+	line  int // Line index in content
+	start int // Byte offset of the occurrence within the line
+	end   int // Byte offset just past the occurrence
+	term  int // Index of the matched term (see searchTerms)
 }
 
 // totalMatches returns the total number of search matches
+// (occurrences for full-text search, sections for option/description search)
 func (v Viewer) totalMatches() int {
-	if len(v.matchingLines) > 0 {
-		return len(v.matchingLines)
+	if len(v.matches) > 0 {
+		return len(v.matches)
 	}
 	return len(v.filteredIndices)
 }
 
-// findMatchingLines returns line numbers where the search query appears (for full-text search)
-func (v Viewer) findMatchingLines() []int {
-	var lineNums []int
-	query := strings.ToLower(v.searchQuery)
-	for i, line := range v.content.Lines {
-		if strings.Contains(strings.ToLower(line), query) {
-			lineNums = append(lineNums, i)
+// findMatches returns every occurrence of the search terms (for full-text search),
+// ordered by line then offset
+func (v Viewer) findMatches() []searchMatch {
+	terms := v.queryTerms()
+	if len(terms) == 0 {
+		return nil
+	}
+	occurrences := func(line string) []searchMatch {
+		return termOccurrences(v.foldCase(line), terms, v.wholeWord)
+	}
+	if v.searchRegex != nil {
+		occurrences = func(line string) []searchMatch {
+			return regexOccurrences(line, v.searchRegex)
+		}
+	}
+	var matches []searchMatch
+	start, end := v.searchRange()
+	for i := start; i <= end; i++ {
+		for _, m := range occurrences(v.lines()[i]) {
+			m.line = i
+			matches = append(matches, m)
+		}
+	}
+	return matches
+}
+
+// queryTerms returns the terms of the active full-text query: the whole
+// query for a phrase search, otherwise its searchTerms
+func (v Viewer) queryTerms() []string {
+	if v.searchPhrase {
+		return []string{v.foldCase(v.searchQuery)}
+	}
+	return searchTerms(v.searchQuery, v.caseSensitive())
+}
+
+// searchTerms splits a full-text query into terms separated by spaces, so
+// "timeout retry" highlights both words. Double quotes keep a phrase together
+// ("\"long listing\""). Terms are lowercased unless caseSensitive, and
+// repeated terms are dropped.
+func searchTerms(query string, caseSensitive bool) []string {
+	var terms []string
+	add := func(term string) {
+		if !caseSensitive {
+			term = strings.ToLower(term)
+		}
+		if term != "" && !slices.Contains(terms, term) {
+			terms = append(terms, term)
 		}
 	}
-	return lineNums
+	for i, part := range strings.Split(query, "\"") {
+		if i%2 == 1 {
+			add(part) // Inside quotes
+			continue
+		}
+		for _, field := range strings.Fields(part) {
+			add(field)
+		}
+	}
+	return terms
+}
+
+// termOccurrences returns the non-overlapping occurrences of terms in
+// lowerText, ordered by offset. Both are lowercased, except for a
+// case-sensitive search. Where occurrences overlap the earlier (then
+// longer) one wins. With wholeWord, occurrences joined to a word character
+// on either side are skipped. Line is left unset.
+func termOccurrences(lowerText string, terms []string, wholeWord bool) []searchMatch {
+	var all []searchMatch
+	for t, term := range terms {
+		for offset := 0; ; {
+			idx := strings.Index(lowerText[offset:], term)
+			if idx == -1 {
+				break
+			}
+			start := offset + idx
+			if wholeWord && !isWholeWord(lowerText, start, start+len(term)) {
+				offset = start + 1
+				continue
+			}
+			all = append(all, searchMatch{start: start, end: start + len(term), term: t})
+			offset = start + len(term)
+		}
+	}
+	if len(terms) == 1 {
+		return all
+	}
+
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].start != all[j].start {
+			return all[i].start < all[j].start
+		}
+		return all[i].end > all[j].end
+	})
+	matches := all[:0]
+	for _, m := range all {
+		if n := len(matches); n > 0 && m.start < matches[n-1].end {
+			continue
+		}
+		matches = append(matches, m)
+	}
+	return matches
+}
+
+// lineMatches returns the occurrences on the given line and the index of the
+// first one within v.matches
+func (v Viewer) lineMatches(lineNum int) ([]searchMatch, int) {
+	first := sort.Search(len(v.matches), func(i int) bool { return v.matches[i].line >= lineNum })
+	last := first
+	for last < len(v.matches) && v.matches[last].line == lineNum {
+		last++
+	}
+	return v.matches[first:last], first
+}
+
+// searchRange returns the inclusive line range full-text search covers:
+// the scoped man section if set, otherwise the whole page
+func (v Viewer) searchRange() (start, end int) {
+	start, end = 0, len(v.lines())-1
+	if v.searchScope != nil {
+		start = max(v.searchScope.StartLine, 0)
+		end = min(v.searchScope.EndLine, end)
+	}
+	return start, end
 }
 
 // scrollToCurrentMatch scrolls the viewport to show the current match
 func (v *Viewer) scrollToCurrentMatch() {
 	var targetLine int
 
-	if len(v.matchingLines) > 0 {
-		// Line-based search (full-text)
-		targetLine = v.matchingLines[v.currentMatch]
+	if len(v.matches) > 0 {
+		// Occurrence-based search (full-text)
+		m := v.matches[v.currentMatch]
+		targetLine = m.line
+		// Scroll horizontally if the occurrence is outside the visible columns
+		line := v.lines()[m.line]
+		startCol, endCol := ansi.StringWidth(line[:m.start]), ansi.StringWidth(line[:m.end])
+		if startCol < v.hScrollOffset || endCol > v.hScrollOffset+v.visibleContentWidth() {
+			v.hScrollOffset = min(max(startCol-hScrollStep, 0), v.maxHScroll())
+		}
 	} else if len(v.filteredIndices) > 0 {
 		// Section-based search
 		sectionIdx := v.filteredIndices[v.currentMatch]
@@ -640,12 +1732,15 @@ func (v *Viewer) scrollToCurrentMatch() {
 		return
 	}
 
+	// Remember where we were so ctrl+o can return
+	v.pushJump()
+
 	// Scroll to center the target line in viewport
 	v.scrollOffset = targetLine - v.viewportHeight()/2
 	if v.scrollOffset < 0 {
 		v.scrollOffset = 0
 	}
-	maxScroll := len(v.content.Lines) - v.viewportHeight()
+	maxScroll := len(v.lines()) - v.viewportHeight()
 	if maxScroll < 0 {
 		maxScroll = 0
 	}
@@ -656,17 +1751,17 @@ func (v *Viewer) scrollToCurrentMatch() {
 
 // viewportHeight returns the height available for content (minus status lines)
 func (v Viewer) viewportHeight() int {
-	// Reserve 3 lines: 1 for title, 1 for command line, 1 for help
-	return v.height - 3
+	// Reserve 3 lines: 1 for title, 1 for command line, 1 for help. Keys
+	// still move the cursor while the terminal is too small to render, so
+	// keep at least one line to keep cursor and scroll math in range.
+	return max(v.height-3, 1)
 }
 
 // isLineMatching checks if a line number is a match (either direct line match or within matching section)
 func (v Viewer) isLineMatching(lineNum int) bool {
-	// Check line-based matches first
-	for _, matchLine := range v.matchingLines {
-		if lineNum == matchLine {
-			return true
-		}
+	// Check full-text occurrences first
+	if occurrences, _ := v.lineMatches(lineNum); len(occurrences) > 0 {
+		return true
 	}
 	// Check section-based matches
 	for _, idx := range v.filteredIndices {
@@ -680,9 +1775,9 @@ func (v Viewer) isLineMatching(lineNum int) bool {
 
 // isCurrentMatchLine checks if a line number is the currently selected match
 func (v Viewer) isCurrentMatchLine(lineNum int) bool {
-	if len(v.matchingLines) > 0 {
-		// Line-based search (full-text)
-		return lineNum == v.matchingLines[v.currentMatch]
+	if len(v.matches) > 0 {
+		// Occurrence-based search (full-text) - the line holding the current occurrence
+		return lineNum == v.matches[v.currentMatch].line
 	}
 	if len(v.filteredIndices) > 0 {
 		// Section-based search - current match is the start line of the section
@@ -693,8 +1788,38 @@ func (v Viewer) isCurrentMatchLine(lineNum int) bool {
 	return false
 }
 
+// hasOptions reports whether any option sections were parsed. Pages without
+// options (common in sections 5 and 7) show a placeholder in the sidebar,
+// which then can't take focus.
+func (v Viewer) hasOptions() bool {
+	return len(v.content.Sections) > 0
+}
+
+// cyclePane moves focus to the next (step 1) or previous (step -1) pane in
+// the configured order, skipping the sidebar when the page has no options.
+// With a single usable pane focus stays where it is.
+func (v *Viewer) cyclePane(step int) {
+	n := len(v.paneOrder)
+	// A pane left out of the order (focused by h/l or a click) cycles on
+	// from the content pane's place
+	pos := slices.Index(v.paneOrder, v.focusPane)
+	if pos == -1 {
+		pos = slices.Index(v.paneOrder, paneContent)
+	}
+	for range n {
+		pos = (pos + n + step) % n
+		if p := v.paneOrder[pos]; p != paneSidebar || v.hasOptions() {
+			v.focusPane = p
+			return
+		}
+	}
+}
+
 // sidebarWidth returns the width of the sidebar
 func (v Viewer) sidebarWidth() int {
+	if v.contentOnly() {
+		return 0
+	}
 	return 30
 }
 
@@ -716,13 +1841,33 @@ func (v Viewer) getDisplayedSectionIndices() []int {
 	}
 
 	// Full-text search - find sections that contain the search query
-	if len(v.matchingLines) > 0 {
+	if len(v.matches) > 0 {
 		var indices []int
-		for i, section := range v.content.Sections {
-			if section.MatchesQuery(v.searchQuery) {
-				indices = append(indices, i)
+		start, end := v.searchRange()
+		var matched []int
+		if v.searchRegex != nil {
+			// The index can't match a regex; check every option's lines
+			for i := range v.content.Sections {
+				matched = append(matched, i)
+			}
+		} else {
+			for _, term := range v.queryTerms() {
+				matched = append(matched, v.sectionIndex.MatchQuery(term, v.caseSensitive())...)
 			}
 		}
+		slices.Sort(matched)
+		for _, i := range slices.Compact(matched) {
+			section := v.content.Sections[i]
+			if section.StartLine < start || section.StartLine > end {
+				continue
+			}
+			// The index matches substrings, so check a whole word (or the
+			// regex) matched
+			if (v.wholeWord || v.searchRegex != nil) && !v.hasMatchIn(section.StartLine, section.EndLine) {
+				continue
+			}
+			indices = append(indices, i)
+		}
 		return indices
 	}
 
@@ -730,11 +1875,29 @@ func (v Viewer) getDisplayedSectionIndices() []int {
 	return nil
 }
 
+// hasMatchIn reports whether a full-text match falls within lines start
+// to end
+func (v Viewer) hasMatchIn(start, end int) bool {
+	i := sort.Search(len(v.matches), func(i int) bool { return v.matches[i].line >= start })
+	return i < len(v.matches) && v.matches[i].line <= end
+}
+
 // sectionsPaneWidth returns the width of the right sections pane
 func (v Viewer) sectionsPaneWidth() int {
+	if v.contentOnly() {
+		return 0
+	}
 	return 22
 }
 
+// positionStatus describes the content cursor's place in the page like less
+// does, e.g. "curl(1)  line 120/980  12%"
+func (v Viewer) positionStatus() string {
+	currentLine := v.scrollOffset + v.contentCursor
+	total := len(v.lines())
+	return fmt.Sprintf("%s  line %d/%d  %d%%", v.pageRef(), min(currentLine+1, total), total, calculatePercentage(currentLine, total))
+}
+
 // calculatePercentage returns the percentage position (0-100) given current position and total items
 func calculatePercentage(current, total int) int {
 	if total <= 1 {
@@ -755,20 +1918,87 @@ func (v Viewer) currentManSectionIndex() int {
 	currentLine := v.scrollOffset + v.contentCursor
 	currentIdx := 0
 
-	for i, section := range sections {
-		if section.StartLine <= currentLine {
-			currentIdx = i
-		} else {
-			break
+	for i, section := range sections {
+		if section.StartLine <= currentLine {
+			currentIdx = i
+		} else {
+			break
+		}
+	}
+
+	return currentIdx
+}
+
+// contentWidth returns the width of the content pane
+func (v Viewer) contentWidth() int {
+	if v.rawView {
+		return v.width - 2
+	}
+	if v.readerMode {
+		return min(v.width-2, v.readerWidth())
+	}
+	return v.width - v.sidebarWidth() - v.sectionsPaneWidth() - 3 // -3 for the pane borders
+}
+
+// readerWidth returns the content pane width in reader mode: the page's
+// formatted width plus room for the border, padding, and arrow indicator
+func (v Viewer) readerWidth() int {
+	return v.pageWidth() + 6
+}
+
+// pageWidth returns the width man formatted the page at
+func (v Viewer) pageWidth() int {
+	if v.fetchOpts.Width == 0 {
+		return parse.DefaultWidth
+	}
+	return v.fetchOpts.Width
+}
+
+// toggleZoom re-fetches the page at the content pane's width, so paragraphs
+// reflow into the spare space of a wide terminal, or back at the width it
+// had before. applyFetched keeps the reading position by line ratio.
+func (v *Viewer) toggleZoom() tea.Cmd {
+	opts := v.fetchOpts
+	if v.zoomed {
+		opts.Width = v.unzoomedWidth
+		width := opts.Width
+		if width == 0 {
+			width = parse.DefaultWidth
 		}
+		return v.refetchZoom(opts, fmt.Sprintf("Formatted at %d columns", width), zoomOut)
 	}
 
-	return currentIdx
+	width := min(v.visibleContentWidth(), parse.MaxWidth)
+	if width <= v.pageWidth() {
+		v.statusMsg = "The page already fills the content pane"
+		return nil
+	}
+	opts.Width = width
+	return v.refetchZoom(opts, fmt.Sprintf("Zoomed to %d columns (z to restore)", width), zoomIn)
 }
 
-// contentWidth returns the width of the content pane
-func (v Viewer) contentWidth() int {
-	return v.width - v.sidebarWidth() - v.sectionsPaneWidth() - 2 // -2 for borders
+// minCenterSlack is how many spare columns the content pane needs beyond the
+// page's width before centerContent centers the page
+const minCenterSlack = 16
+
+// contentMargin returns the left margin that centers the page's text in the
+// content pane when centerContent is on and the pane is much wider than the
+// page, so an 80-column page isn't glued to the border of a wide pane
+func (v Viewer) contentMargin() int {
+	if !v.centerContent {
+		return 0
+	}
+	slack := v.contentWidth() - 4 - v.pageWidth() // -4 for the border and the arrow prefix
+	if slack < minCenterSlack {
+		return 0
+	}
+	return slack / 2
+}
+
+// readerMargin returns the left margin that centers the content pane in
+// reader mode
+func (v Viewer) readerMargin() int {
+	return max((v.width-v.contentWidth()-1)/2, 0) // -1 for the left border
 }
 
 // truncateOption truncates an option string to fit in the sidebar
@@ -789,19 +2019,19 @@ func (v Viewer) renderSidebar() string {
 	vpHeight := v.viewportHeight() - 1 // -1 for title
 
 	// Sidebar styles
-	var borderColor, titleBg lipgloss.Color
+	border, borderColor := v.paneBorder(v.focusPane == paneSidebar)
+	titleBg := lipgloss.Color("238") // Dark gray when not focused
 	if v.focusPane == paneSidebar {
-		borderColor = lipgloss.Color("212") // Pink when focused
-		titleBg = lipgloss.Color("62")      // Brighter purple when focused
-	} else {
-		borderColor = lipgloss.Color("241") // Gray when not focused
-		titleBg = lipgloss.Color("238")     // Dark gray when not focused
+		titleBg = lipgloss.Color("62") // Brighter purple when focused
 	}
 
 	// Title bar with percentage completion
 	displayedIndices := v.getDisplayedSectionIndices()
-	percentage := calculatePercentage(v.sidebarCursor, len(displayedIndices))
-	titleText := fmt.Sprintf("OPTIONS (%d%%)", percentage)
+	titleText := "OPTIONS"
+	if len(displayedIndices) > 0 {
+		percentage := calculatePercentage(v.sidebarCursor, len(displayedIndices))
+		titleText = fmt.Sprintf("OPTIONS (%d%%)", percentage)
+	}
 
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
@@ -822,19 +2052,51 @@ func (v Viewer) renderSidebar() string {
 		Foreground(lipgloss.Color("252")).
 		Width(sidebarW - 2)
 
+	// Explain an empty sidebar rather than leaving a blank box
+	placeholder := ""
+	if len(displayedIndices) == 0 {
+		placeholder = "No matching options"
+		if !v.hasOptions() {
+			placeholder = "No options in this page"
+		}
+	}
+	placeholderStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Italic(true).
+		Width(sidebarW - 2)
+
+	// The summary line under each option when sidebarExplain is on
+	summaryStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("243")).
+		Width(sidebarW - 2)
+	selectedSummaryStyle := sidebarSelectedStyle.
+		Bold(false).
+		Foreground(lipgloss.Color("250"))
+
+	rowsPerEntry := v.sidebarRowsPerEntry()
 	for i := 0; i < vpHeight; i++ {
-		displayIdx := v.sidebarScrollOffset + i
+		displayIdx := v.sidebarScrollOffset + i/rowsPerEntry
 		var line string
-		if displayIdx < len(displayedIndices) {
+		if placeholder != "" && i == 0 {
+			line = placeholderStyle.Render("  " + truncateOption(placeholder, sidebarW-4))
+		} else if displayIdx < len(displayedIndices) && i%rowsPerEntry == 1 {
+			section := v.content.Sections[displayedIndices[displayIdx]]
+			summary := "    " + ansi.Truncate(parse.SummarizeExplanation(section), sidebarW-6, "…")
+			if displayIdx == v.sidebarCursor {
+				line = selectedSummaryStyle.Render(summary)
+			} else {
+				line = summaryStyle.Render(summary)
+			}
+		} else if displayIdx < len(displayedIndices) {
 			sectionIdx := displayedIndices[displayIdx]
 			section := v.content.Sections[sectionIdx]
 			// Extract only the option flags, not the description
 			optFlags := parse.ExtractOptionFlags(section.Option)
 			opt := truncateOption(optFlags, sidebarW-4)
 			if displayIdx == v.sidebarCursor {
-				line = sidebarSelectedStyle.Render("> " + opt)
+				line = v.renderSidebarOption("> ", opt, sidebarSelectedStyle)
 			} else {
-				line = sidebarNormalStyle.Render("  " + opt)
+				line = v.renderSidebarOption("  ", opt, sidebarNormalStyle)
 			}
 		} else {
 			line = sidebarNormalStyle.Render("")
@@ -847,7 +2109,7 @@ func (v Viewer) renderSidebar() string {
 
 	// Wrap sidebar content in a border
 	sidebarStyle := lipgloss.NewStyle().
-		BorderStyle(lipgloss.RoundedBorder()).
+		BorderStyle(border).
 		BorderForeground(borderColor).
 		BorderRight(true).
 		Width(sidebarW)
@@ -855,6 +2117,83 @@ func (v Viewer) renderSidebar() string {
 	return sidebarStyle.Render(b.String())
 }
 
+// renderSidebarOption renders a sidebar row, highlighting the parts of the
+// option flags that matched the active search. Segments are styled
+// individually so the row's background survives around the highlights.
+func (v Viewer) renderSidebarOption(prefix, opt string, rowStyle lipgloss.Style) string {
+	ranges := v.sidebarMatchRanges(opt)
+	if len(ranges) == 0 {
+		return rowStyle.Render(prefix + opt)
+	}
+
+	matchStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("220")). // Yellow background, as in content
+		Foreground(lipgloss.Color("0")).
+		Bold(true)
+	segmentStyle := rowStyle.UnsetWidth()
+
+	var b strings.Builder
+	b.WriteString(segmentStyle.Render(prefix))
+	lastEnd := 0
+	for _, r := range ranges {
+		if r[0] > lastEnd {
+			b.WriteString(segmentStyle.Render(opt[lastEnd:r[0]]))
+		}
+		b.WriteString(matchStyle.Render(opt[r[0]:r[1]]))
+		lastEnd = r[1]
+	}
+	if lastEnd < len(opt) {
+		b.WriteString(segmentStyle.Render(opt[lastEnd:]))
+	}
+	if padding := rowStyle.GetWidth() - len(prefix) - len(opt); padding > 0 {
+		b.WriteString(segmentStyle.Render(strings.Repeat(" ", padding)))
+	}
+	return b.String()
+}
+
+// sidebarMatchRanges returns the byte ranges of opt matching the active
+// search: the exactly matching flags for an exact option search, every
+// occurrence of each term for full-text search, otherwise every occurrence of
+// the query (case-insensitive unless smartcase applies)
+func (v Viewer) sidebarMatchRanges(opt string) [][2]int {
+	if v.searchQuery == "" {
+		return nil
+	}
+
+	var ranges [][2]int
+	if v.searchType == searchOptionExact {
+		for _, loc := range flagTokenRe.FindAllStringIndex(opt, -1) {
+			token := opt[loc[0]:loc[1]]
+			if parse.MatchesFlag(token, v.searchQuery) || parse.MatchesGroupedFlag(token, v.searchQuery) {
+				ranges = append(ranges, [2]int{loc[0], loc[1]})
+			}
+		}
+		return ranges
+	}
+
+	if v.searchType == searchAll && v.searchRegex != nil {
+		for _, m := range regexOccurrences(opt, v.searchRegex) {
+			ranges = append(ranges, [2]int{m.start, m.end})
+		}
+		return ranges
+	}
+
+	terms := []string{v.foldCase(v.searchQuery)}
+	wholeWord := false
+	if v.searchType == searchAll {
+		terms = v.queryTerms()
+		wholeWord = v.wholeWord
+	}
+	for _, m := range termOccurrences(v.foldCase(opt), terms, wholeWord) {
+		ranges = append(ranges, [2]int{m.start, m.end})
+	}
+	return ranges
+}
+
+// flagTokenRe matches the individual flags in an option's flag text, split
+// the same way as parse.Section.MatchesOptionExact
+var flagTokenRe = regexp.MustCompile(`[^, ]+`)
+
 // highlightClickableOptions highlights option flags in a line to show they are clickable
 func (v Viewer) highlightClickableOptions(line string) string {
 	// Don't highlight options in heavily indented lines (examples, code blocks)
@@ -914,13 +2253,103 @@ func (v Viewer) highlightClickableOptions(line string) string {
 	return result.String()
 }
 
+// optionStartLines maps each option definition's start line to its section index
+func (v Viewer) optionStartLines() map[int]int {
+	starts := make(map[int]int, len(v.content.Sections))
+	for i, section := range v.content.Sections {
+		starts[section.StartLine] = i
+	}
+	return starts
+}
+
+// exampleLines returns the set of lines in EXAMPLES code blocks
+func (v Viewer) exampleLines() map[int]bool {
+	lines := make(map[int]bool)
+	if v.rawView {
+		// Blocks refer to parsed line numbers
+		return lines
+	}
+	for _, block := range v.content.Examples {
+		for i := block.StartLine; i <= block.EndLine; i++ {
+			lines[i] = true
+		}
+	}
+	return lines
+}
+
+// manSectionStartLines returns the set of lines holding a man section header
+func (v Viewer) manSectionStartLines() map[int]bool {
+	headers := make(map[int]bool, len(v.content.ManSections))
+	for _, ms := range v.content.ManSections {
+		headers[ms.StartLine] = true
+	}
+	return headers
+}
+
+// sectionRule returns the horizontal rule filling the width columns after a
+// man section header, or "" when lineIdx gets none. The rule only takes the
+// place of padding, so every page line is still one display row.
+func (v Viewer) sectionRule(lineIdx, width int, headerLines map[int]bool) string {
+	if !v.sectionRules || v.rawView || !headerLines[lineIdx] || width < 2 {
+		return ""
+	}
+	return " " + strings.Repeat("─", width-1)
+}
+
+// highlightOptionDefinition styles the flags of an option definition line
+// (e.g. "-r, --recursive") so they stand out from the surrounding prose.
+// The remainder of the line still gets clickable option highlighting.
+func (v Viewer) highlightOptionDefinition(line string, section parse.Section) string {
+	flags := parse.ExtractOptionFlags(section.Option)
+	start := strings.Index(line, flags)
+	if flags == "" || start == -1 {
+		return v.highlightClickableOptions(line)
+	}
+	end := start + len(flags)
+
+	flagStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("214")). // Amber
+		Bold(true)
+
+	return line[:start] + flagStyle.Render(flags) + v.highlightClickableOptions(line[end:])
+}
+
+// isWholeWord reports whether text[start:end] is not part of a longer word:
+// it doesn't continue a word character before start or after end. So "port"
+// matches in "--port" but not in "report", and "-l" doesn't match in "-la".
+func isWholeWord(text string, start, end int) bool {
+	before, _ := utf8.DecodeLastRuneInString(text[:start])
+	first, _ := utf8.DecodeRuneInString(text[start:end])
+	last, _ := utf8.DecodeLastRuneInString(text[start:end])
+	after, _ := utf8.DecodeRuneInString(text[end:])
+	return !(isWordRune(before) && isWordRune(first)) && !(isWordRune(last) && isWordRune(after))
+}
+
+// isWordRune reports whether r is a letter, digit or underscore
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
 // isAlphanumeric checks if a byte is alphanumeric
 func isAlphanumeric(c byte) bool {
 	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
 }
 
-// highlightSearchTerm highlights occurrences of the search query in a line
-func (v Viewer) highlightSearchTerm(line string) string {
+// termStyles are the styles of occurrences other than the current one, one
+// per search term (cycling) so several terms can be told apart
+var termStyles = []lipgloss.Style{
+	lipgloss.NewStyle().Background(lipgloss.Color("220")).Foreground(lipgloss.Color("0")).Bold(true), // Yellow
+	lipgloss.NewStyle().Background(lipgloss.Color("117")).Foreground(lipgloss.Color("0")).Bold(true), // Light blue
+	lipgloss.NewStyle().Background(lipgloss.Color("156")).Foreground(lipgloss.Color("0")).Bold(true), // Light green
+	lipgloss.NewStyle().Background(lipgloss.Color("218")).Foreground(lipgloss.Color("0")).Bold(true), // Pink
+}
+
+// highlightSearchTerm highlights occurrences of the search terms in a line,
+// each term in its own color. The current occurrence (the one n/N navigated
+// to) gets a distinct style.
+// line is the visible slice of the line (see hScrollStart); occurrences
+// partly scrolled or truncated off are clipped to it.
+func (v Viewer) highlightSearchTerm(line string, lineNum int) string {
 	if v.searchQuery == "" {
 		return line
 	}
@@ -931,41 +2360,44 @@ func (v Viewer) highlightSearchTerm(line string) string {
 		return line
 	}
 
-	// Style for the search term itself - bright yellow on dark red for maximum visibility
-	termStyle := lipgloss.NewStyle().
-		Background(lipgloss.Color("208")). // Bright orange background
-		Foreground(lipgloss.Color("0")).   // Black text
-		Bold(true)
+	// Style for the current occurrence - bright for maximum visibility
+	currentTermStyle := lipgloss.NewStyle().
+		Background(v.currentMatchColor).
+		Foreground(lipgloss.Color("0")). // Black text
+		Bold(true).
+		Underline(true)
 
-	// Case-insensitive search and replace
-	lowerLine := strings.ToLower(line)
-	lowerQuery := strings.ToLower(v.searchQuery)
+	occurrences, firstIdx := v.lineMatches(lineNum)
+	offset := v.hScrollStart(v.lines()[lineNum])
 
 	var result strings.Builder
 	lastEnd := 0
-
-	for {
-		idx := strings.Index(lowerLine[lastEnd:], lowerQuery)
-		if idx == -1 {
-			// No more matches, append the rest
-			result.WriteString(line[lastEnd:])
+	for i, m := range occurrences {
+		// Shift into the visible slice and clip
+		start := max(m.start-offset, 0)
+		end := min(m.end-offset, len(line))
+		if start >= len(line) {
 			break
 		}
-
-		// Append text before the match
-		matchStart := lastEnd + idx
-		result.WriteString(line[lastEnd:matchStart])
-
-		// Append the highlighted match (preserve original case)
-		matchEnd := matchStart + len(v.searchQuery)
-		result.WriteString(termStyle.Render(line[matchStart:matchEnd]))
-
-		lastEnd = matchEnd
+		if end <= start {
+			continue
+		}
+		result.WriteString(line[lastEnd:start])
+		style := termStyles[m.term%len(termStyles)]
+		if firstIdx+i == v.currentMatch {
+			style = currentTermStyle
+		}
+		result.WriteString(style.Render(line[start:end]))
+		lastEnd = end
 	}
+	result.WriteString(line[lastEnd:])
 
 	return result.String()
 }
 
+// clippedMarker ends content lines that continue past the pane's right edge
+var clippedMarker = lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render("›")
+
 // renderContent renders the right content pane
 func (v Viewer) renderContent() string {
 	var b strings.Builder
@@ -973,19 +2405,19 @@ func (v Viewer) renderContent() string {
 	contentW := v.contentWidth() - 2   // Account for border
 
 	// Content pane border and title color based on focus
-	var borderColor, titleBg lipgloss.Color
+	border, borderColor := v.paneBorder(v.focusPane == paneContent)
+	titleBg := lipgloss.Color("238") // Dark gray when not focused
 	if v.focusPane == paneContent {
-		borderColor = lipgloss.Color("212") // Pink when focused
-		titleBg = lipgloss.Color("62")      // Brighter purple when focused
-	} else {
-		borderColor = lipgloss.Color("241") // Gray when not focused
-		titleBg = lipgloss.Color("238")     // Dark gray when not focused
+		titleBg = lipgloss.Color("62") // Brighter purple when focused
 	}
 
 	// Title bar with percentage completion
 	currentLine := v.scrollOffset + v.contentCursor
-	percentage := calculatePercentage(currentLine, len(v.content.Lines))
+	percentage := calculatePercentage(currentLine, len(v.lines()))
 	titleText := fmt.Sprintf("CONTENT (%d%%)", percentage)
+	if v.hScrollOffset > 0 {
+		titleText += fmt.Sprintf(" →%d", v.hScrollOffset)
+	}
 
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
@@ -996,15 +2428,21 @@ func (v Viewer) renderContent() string {
 	b.WriteString(titleStyle.Render(titleText))
 	b.WriteString("\n")
 
+	// Below the title, a narrow page is centered by indenting every row and
+	// laying it out in the remaining width
+	margin := v.contentMargin()
+	indent := strings.Repeat(" ", margin)
+	contentW -= margin
+
 	// Style for the current match (the one we navigated to with n/N)
 	currentMatchStyle := lipgloss.NewStyle().
-		Background(lipgloss.Color("208")). // Bright orange background
-		Foreground(lipgloss.Color("0")).   // Black text
+		Background(v.currentMatchColor).
+		Foreground(lipgloss.Color("0")). // Black text
 		Bold(true)
 
-	// Style for other matching lines (subtle green background)
+	// Style for other matching lines (subtle background)
 	matchingLineStyle := lipgloss.NewStyle().
-		Background(lipgloss.Color("22")).
+		Background(v.matchColor).
 		Foreground(lipgloss.Color("252"))
 
 	// Style for current line when content pane is focused (subtle underline effect)
@@ -1014,59 +2452,107 @@ func (v Viewer) renderContent() string {
 
 	// Arrow indicator for current match
 	arrowStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("208")). // Bright orange
+		Foreground(v.currentMatchColor).
 		Bold(true)
 
+	// Option definition lines get their flags styled distinctly and man
+	// section headers are bolded, since col -b strips man's own emphasis
+	optionStarts := v.optionStartLines()
+	headerLines := v.manSectionStartLines()
+	examples := v.exampleLines()
+
+	// Example code gets its own background so it reads as a block
+	exampleStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("235")).
+		Foreground(lipgloss.Color("150"))
+
+	// Rules after section headers are dim so they mark boundaries without
+	// competing with the text
+	ruleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240"))
+
 	for i := 0; i < vpHeight; i++ {
+		b.WriteString(indent)
 		lineIdx := v.scrollOffset + i
 		var line string
-		if lineIdx < len(v.content.Lines) {
-			line = v.content.Lines[lineIdx]
-			// Truncate if too long (leave room for arrow indicator)
+		clipped := false // Whether the line continues past the right edge
+		if lineIdx < len(v.lines()) {
+			line = v.lines()[lineIdx]
+			// Skip the horizontally scrolled-off part
+			line = line[v.hScrollStart(line):]
+			// Truncate if too long (leave room for arrow indicator). Widths
+			// are in columns so multi-byte characters don't cut lines short.
 			maxLen := contentW - 2 // Reserve 2 chars for "→ " prefix
-			if len(line) > maxLen {
-				line = line[:maxLen]
+			if ansi.StringWidth(line) > maxLen {
+				// Keep the last column for the marker
+				line = ansi.Truncate(line, maxLen-1, "")
+				clipped = true
 			}
 		}
 
+		lineWidth := ansi.StringWidth(line)
+		if clipped {
+			lineWidth++ // The marker
+		}
+
 		// Highlight matching lines and search terms
 		if v.searchQuery != "" && v.isCurrentMatchLine(lineIdx) {
-			// This is the CURRENT match - use distinct highlighting with arrow
-			highlightedLine := v.highlightSearchTerm(line)
-			padding := contentW - 2 - len(line) // -2 for arrow prefix
+			// This is the line of the CURRENT match - mark it with an arrow
+			highlightedLine := v.highlightSearchTerm(line, lineIdx)
+			padding := contentW - 2 - lineWidth // -2 for arrow prefix
 			if padding > 0 {
 				highlightedLine += strings.Repeat(" ", padding)
 			}
-			b.WriteString(arrowStyle.Render("→ ") + currentMatchStyle.Render(highlightedLine))
+			lineStyle := currentMatchStyle
+			if len(v.matches) > 0 {
+				// Full-text: the current occurrence itself is highlighted, so
+				// the line only gets the subtle match background
+				lineStyle = matchingLineStyle
+			}
+			b.WriteString(arrowStyle.Render("→ ") + lineStyle.Render(highlightedLine))
 		} else if v.searchQuery != "" && v.isLineMatching(lineIdx) {
 			// Other matching lines
-			highlightedLine := v.highlightSearchTerm(line)
-			padding := contentW - 2 - len(line)
+			highlightedLine := v.highlightSearchTerm(line, lineIdx)
+			padding := contentW - 2 - lineWidth
 			if padding > 0 {
 				highlightedLine += strings.Repeat(" ", padding)
 			}
-			b.WriteString("  " + matchingLineStyle.Render(highlightedLine))
+			if v.matchBackgrounds {
+				highlightedLine = matchingLineStyle.Render(highlightedLine)
+			}
+			b.WriteString("  " + highlightedLine)
 		} else if v.focusPane == paneContent && i == v.contentCursor {
 			// Highlight the cursor line when content pane is focused
 			// Highlight clickable options first, then add background for cursor line
-			highlightedLine := v.highlightClickableOptions(line)
+			highlightedLine := v.highlightLine(line, lineIdx, optionStarts, headerLines)
 			// For cursor line, we need to preserve option highlighting while adding background
 			// So we apply background color inline instead of using a wrapper style
-			padding := contentW - 2 - len(line)
+			padding := contentW - 2 - lineWidth
 			paddedLine := highlightedLine
-			if padding > 0 {
+			if rule := v.sectionRule(lineIdx, padding, headerLines); rule != "" {
+				paddedLine += currentLineStyle.Foreground(lipgloss.Color("240")).Render(rule)
+			} else if padding > 0 {
 				paddedLine += currentLineStyle.Render(strings.Repeat(" ", padding))
 			}
 			b.WriteString("  " + paddedLine)
+		} else if examples[lineIdx] {
+			// Example code, shown as is
+			padding := contentW - 2 - lineWidth
+			b.WriteString("  " + exampleStyle.Render(line+strings.Repeat(" ", max(padding, 0))))
 		} else {
-			// Normal lines - highlight clickable options
-			highlightedLine := v.highlightClickableOptions(line)
-			padding := contentW - 2 - len(line)
-			if padding > 0 {
+			// Normal lines - highlight option definitions and clickable options
+			highlightedLine := v.highlightLine(line, lineIdx, optionStarts, headerLines)
+			padding := contentW - 2 - lineWidth
+			if rule := v.sectionRule(lineIdx, padding, headerLines); rule != "" {
+				highlightedLine += ruleStyle.Render(rule)
+			} else if padding > 0 {
 				highlightedLine += strings.Repeat(" ", padding)
 			}
 			b.WriteString("  " + highlightedLine)
 		}
+		if clipped {
+			b.WriteString(clippedMarker)
+		}
 		if i < vpHeight-1 {
 			b.WriteString("\n")
 		}
@@ -1074,7 +2560,7 @@ func (v Viewer) renderContent() string {
 
 	// Wrap content in a border
 	contentStyle := lipgloss.NewStyle().
-		BorderStyle(lipgloss.RoundedBorder()).
+		BorderStyle(border).
 		BorderForeground(borderColor).
 		BorderLeft(true).
 		Width(v.contentWidth())
@@ -1082,33 +2568,135 @@ func (v Viewer) renderContent() string {
 	return contentStyle.Render(b.String())
 }
 
+// highlightLine applies emphasis when enabled: man section headers are
+// bolded, option definition lines get their flags styled, and other lines get
+// the bold/underline man itself used. Otherwise (or with emphasis off) lines
+// only get clickable option highlighting. The raw view shows lines as is.
+func (v Viewer) highlightLine(line string, lineIdx int, optionStarts map[int]int, headerLines map[int]bool) string {
+	if v.rawView {
+		return line
+	}
+	if v.emphasis {
+		if headerLines[lineIdx] {
+			return lipgloss.NewStyle().Bold(true).Render(line)
+		}
+		if sectionIdx, ok := optionStarts[lineIdx]; ok {
+			return v.highlightOptionDefinition(line, v.content.Sections[sectionIdx])
+		}
+		if lineIdx < len(v.content.Styles) && len(v.content.Styles[lineIdx]) > 0 {
+			return v.renderStyledRuns(line, v.content.Styles[lineIdx], v.hScrollStart(v.content.Lines[lineIdx]))
+		}
+	}
+	return v.highlightClickableOptions(line)
+}
+
+// renderStyledRuns renders a line with man's bold/underline runs. Unstyled
+// text between runs still gets clickable option highlighting. line is the
+// visible slice of the line, offset bytes in (see hScrollStart); runs
+// outside it are clipped.
+func (v Viewer) renderStyledRuns(line string, runs []parse.StyledRun, offset int) string {
+	var result strings.Builder
+	lastEnd := 0
+	for _, run := range runs {
+		start := max(run.Start-offset, 0)
+		end := min(run.End-offset, len(line))
+		if start >= len(line) {
+			break
+		}
+		if end <= start {
+			continue
+		}
+		result.WriteString(v.highlightClickableOptions(line[lastEnd:start]))
+		style := lipgloss.NewStyle().
+			Bold(run.Style&parse.StyleBold != 0).
+			Underline(run.Style&parse.StyleUnderline != 0)
+		result.WriteString(style.Render(line[start:end]))
+		lastEnd = end
+	}
+	result.WriteString(v.highlightClickableOptions(line[lastEnd:]))
+	return result.String()
+}
+
+// sectionsPaneHighlight returns the sections pane row it highlights: the
+// cursor when the pane is focused, otherwise the section being viewed
+func (v Viewer) sectionsPaneHighlight() int {
+	if v.focusPane == paneSections {
+		return v.sectionsPaneCursor
+	}
+	return v.currentSectionsPaneRow()
+}
+
+// adjustSectionsPaneScroll keeps the highlighted section visible in the
+// sections pane, whether it moved with the pane's cursor or the content
+func (v *Viewer) adjustSectionsPaneScroll() {
+	visible := max(v.viewportHeight()-1, 1) // -1 for title
+	idx := v.sectionsPaneHighlight()
+	if idx < v.sectionsPaneScrollOffset {
+		v.sectionsPaneScrollOffset = idx
+	} else if idx >= v.sectionsPaneScrollOffset+visible {
+		v.sectionsPaneScrollOffset = idx - visible + 1
+	}
+	// Don't leave rows empty after the pane grew
+	v.sectionsPaneScrollOffset = max(min(v.sectionsPaneScrollOffset, len(v.sectionsPaneRows())-visible), 0)
+}
+
+// followSectionsPane scrolls the sections pane of a viewer model returned
+// by an update to its highlighted section
+func followSectionsPane(model tea.Model) tea.Model {
+	v, ok := model.(Viewer)
+	if !ok {
+		return model
+	}
+	v.adjustSectionsPaneScroll()
+	return v
+}
+
+// sectionMatchCounts returns how many matches of the active search each
+// sections pane row holds: occurrences for full-text search, matching
+// options otherwise. Nil when no search is active.
+func (v Viewer) sectionMatchCounts(rows []sectionRow) []int {
+	if v.searchQuery == "" || v.rawView {
+		return nil
+	}
+	lines := make([]int, 0, v.totalMatches())
+	for _, m := range v.matches {
+		lines = append(lines, m.line)
+	}
+	for _, idx := range v.filteredIndices {
+		lines = append(lines, v.content.Sections[idx].StartLine)
+	}
+
+	counts := make([]int, len(rows))
+	for i, r := range rows {
+		ms := v.rowSection(r)
+		for _, line := range lines {
+			if line >= ms.StartLine && line <= ms.EndLine {
+				counts[i]++
+			}
+		}
+	}
+	return counts
+}
+
 // renderSectionsPane renders the right sidebar with man page sections
 func (v Viewer) renderSectionsPane() string {
 	var b strings.Builder
 	paneW := v.sectionsPaneWidth()
 	vpHeight := v.viewportHeight() - 1 // -1 for title
-	sections := v.content.ManSections
+	rows := v.sectionsPaneRows()
+	tree := v.hasSubsections()
 
-	// When focused, use sectionCursor; otherwise show current viewing section
-	var highlightIdx int
-	if v.focusPane == paneSections {
-		highlightIdx = v.sectionCursor
-	} else {
-		highlightIdx = v.currentManSectionIndex()
-	}
+	highlightIdx := v.sectionsPaneHighlight()
 
 	// Sections pane border and title color based on focus
-	var borderColor, titleBg lipgloss.Color
+	border, borderColor := v.paneBorder(v.focusPane == paneSections)
+	titleBg := lipgloss.Color("238") // Dark gray when not focused
 	if v.focusPane == paneSections {
-		borderColor = lipgloss.Color("212") // Pink when focused
-		titleBg = lipgloss.Color("62")      // Brighter purple when focused
-	} else {
-		borderColor = lipgloss.Color("241") // Gray when not focused
-		titleBg = lipgloss.Color("238")     // Dark gray when not focused
+		titleBg = lipgloss.Color("62") // Brighter purple when focused
 	}
 
 	// Title bar with percentage completion
-	percentage := calculatePercentage(highlightIdx, len(sections))
+	percentage := calculatePercentage(highlightIdx, len(rows))
 	titleText := fmt.Sprintf("SECTIONS (%d%%)", percentage)
 
 	titleStyle := lipgloss.NewStyle().
@@ -1131,31 +2719,56 @@ func (v Viewer) renderSectionsPane() string {
 		Foreground(lipgloss.Color("252")).
 		Width(paneW - 4)
 
-	for i := 0; i < vpHeight; i++ {
+	// During a search, sections without matches are dimmed and the others
+	// show their match count
+	noMatchStyle := normalStyle.Foreground(lipgloss.Color("241"))
+	counts := v.sectionMatchCounts(rows)
+
+	for row := 0; row < vpHeight; row++ {
+		i := v.sectionsPaneScrollOffset + row
 		var line string
-		if i < len(sections) {
-			section := sections[i]
-			name := section.Name
-			if len(name) > paneW-6 {
-				name = name[:paneW-9] + "..."
+		if i < len(rows) {
+			r := rows[i]
+			name := v.rowSection(r).Name
+			count := ""
+			if counts != nil && counts[i] > 0 {
+				count = fmt.Sprintf("%d", counts[i])
+			}
+			marker := ""
+			if tree {
+				marker = v.sectionsPaneMarker(r)
+			}
+			nameW := paneW - 6 - ansi.StringWidth(marker) // Prefix, marker and border
+			if count != "" {
+				nameW -= len(count) + 1
 			}
-			if i == highlightIdx {
+			if len(name) > nameW {
+				name = name[:nameW-3] + "..."
+			}
+			if count != "" {
+				name += strings.Repeat(" ", nameW-len(name)+1) + count
+			}
+			name = marker + name
+			switch {
+			case i == highlightIdx:
 				line = selectedStyle.Render("> " + name)
-			} else {
+			case counts != nil && counts[i] == 0:
+				line = noMatchStyle.Render("  " + name)
+			default:
 				line = normalStyle.Render("  " + name)
 			}
 		} else {
 			line = normalStyle.Render("")
 		}
 		b.WriteString(line)
-		if i < vpHeight-1 {
+		if row < vpHeight-1 {
 			b.WriteString("\n")
 		}
 	}
 
 	// Wrap in a border
 	paneStyle := lipgloss.NewStyle().
-		BorderStyle(lipgloss.RoundedBorder()).
+		BorderStyle(border).
 		BorderForeground(borderColor).
 		BorderLeft(true).
 		Width(paneW)
@@ -1193,16 +2806,26 @@ func (v Viewer) renderSectionModal() string {
 		Width(modalWidth - 4).
 		Align(lipgloss.Center)
 	lines = append(lines, titleStyle.Render("Go to Section"))
+
+	// Filter input (shown once filtering starts or a filter is set)
+	if v.sectionFiltering || v.sectionFilter != "" {
+		filterLine := lipgloss.NewStyle().Foreground(lipgloss.Color("212")).Render("/") + v.sectionFilter
+		if v.sectionFiltering {
+			filterLine += "█"
+		}
+		lines = append(lines, filterLine)
+	}
 	lines = append(lines, strings.Repeat("─", modalWidth-4))
 
 	// Section list
+	indices := v.filteredManSectionIndices()
 	for i := 0; i < modalHeight; i++ {
 		idx := v.sectionScrollOffset + i
-		if idx >= len(sections) {
+		if idx >= len(indices) {
 			lines = append(lines, normalStyle.Render(""))
 			continue
 		}
-		section := sections[idx]
+		section := sections[indices[idx]]
 		var line string
 		if idx == v.sectionCursor {
 			line = selectedStyle.Render("> " + section.Name)
@@ -1218,7 +2841,7 @@ func (v Viewer) renderSectionModal() string {
 		Foreground(lipgloss.Color("241")).
 		Width(modalWidth - 4).
 		Align(lipgloss.Center)
-	lines = append(lines, helpStyle.Render("↑↓ navigate • enter select • esc close"))
+	lines = append(lines, helpStyle.Render("↑↓ navigate • g/G top/bottom • / filter • enter select • esc close"))
 
 	content := strings.Join(lines, "\n")
 
@@ -1236,36 +2859,6 @@ func (v Viewer) renderSectionModal() string {
 func (v Viewer) renderHelpModal() string {
 	modalWidth := 50
 
-	// Define all shortcuts
-	shortcuts := []struct {
-		key  string
-		desc string
-	}{
-		{"Navigation", ""},
-		{"↑/k, ↓/j", "Move up/down"},
-		{"←/h, →/l", "Switch panes"},
-		{"tab", "Cycle panes forward"},
-		{"shift+tab", "Cycle panes backward"},
-		{"pgup/ctrl+u", "Page up"},
-		{"pgdown/ctrl+d", "Page down"},
-		{"home", "Go to top"},
-		{"G", "Go to bottom / Open sections"},
-		{"enter", "Select item / Jump to section"},
-		{"", ""},
-		{"Search", ""},
-		{"/", "Search all content"},
-		{"o", "Search options (partial)"},
-		{"O", "Search options (exact)"},
-		{"d", "Search descriptions"},
-		{"n", "Next match"},
-		{"N", "Previous match"},
-		{"esc", "Clear search"},
-		{"", ""},
-		{"Other", ""},
-		{"?", "Show this help"},
-		{"q", "Quit"},
-	}
-
 	// Styles
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
@@ -1291,17 +2884,14 @@ func (v Viewer) renderHelpModal() string {
 	lines = append(lines, titleStyle.Render("Keyboard Shortcuts"))
 	lines = append(lines, strings.Repeat("─", modalWidth-4))
 
-	// Shortcuts list
-	for _, s := range shortcuts {
-		if s.key == "" && s.desc == "" {
-			// Empty line
+	// Shortcuts list, a blank line between groups
+	for i, group := range keymap(v.defaultSearch, v.enterAction) {
+		if i > 0 {
 			lines = append(lines, "")
-		} else if s.desc == "" {
-			// Section header
-			lines = append(lines, headerStyle.Render(s.key))
-		} else {
-			// Key-description pair
-			keyPart := keyStyle.Render(fmt.Sprintf("%-14s", s.key))
+		}
+		lines = append(lines, headerStyle.Render(group.title))
+		for _, s := range group.shortcuts {
+			keyPart := keyStyle.Render(padKey(s.key))
 			descPart := descStyle.Render(s.desc)
 			lines = append(lines, "  "+keyPart+" "+descPart)
 		}
@@ -1327,118 +2917,144 @@ func (v Viewer) renderHelpModal() string {
 	return modalStyle.Render(content)
 }
 
-// handleMouseClick processes mouse click events
+// handleMouseClick navigates to what was clicked: a sidebar option or a
+// sections-pane entry is selected (focusing its pane) and the content jumps
+// to it; a click in the content moves the cursor there and follows a clicked
+// option flag to its definition.
 func (v Viewer) handleMouseClick(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 	// Only handle clicks in normal mode
 	if v.mode != modeNormal {
 		return v, nil
 	}
 
-	// Layout: [sidebar][content][sections]
-	// Y=0: title bar
-	// Y=1: pane titles
-	// Y=2+: content lines
-
-	if msg.Y < 2 {
-		// Click on title bar or pane headers, ignore for now
+	// Layout: Y=0 title bar, Y=1 pane titles, Y=2+ one row per line or item
+	row := msg.Y - 2
+	if row < 0 || row >= v.viewportHeight()-1 {
 		return v, nil
 	}
 
-	clickedViewportLine := msg.Y - 2
-
-	sidebarW := v.sidebarWidth()
-	contentW := v.contentWidth()
+	x := msg.X
+	if v.readerMode && !v.rawView {
+		// Only the centered content pane exists; translate past the margin
+		x -= v.readerMargin()
+	}
+	pane, col, ok := v.paneAt(x)
+	if !ok {
+		return v, nil
+	}
 
-	// Determine which pane was clicked
-	if msg.X < sidebarW {
-		// --- Clicked in Sidebar ---
-		v.focusPane = paneSidebar
+	var cmd tea.Cmd
+	switch pane {
+	case paneSidebar:
+		v.clickSidebar(row)
+	case paneSections:
+		v.clickSections(row)
+	default:
+		cmd = v.clickContent(row, col)
+	}
+	return v, cmd
+}
 
-		displayedIndices := v.getDisplayedSectionIndices()
-		if len(displayedIndices) == 0 {
-			return v, nil
+// paneAt hit-tests screen column x against the pane layout
+// ([sidebar]│ │[content]│[sections], each border one column wide). It
+// returns the pane and the column within its text; ok is false on a border
+// or past the last pane.
+func (v Viewer) paneAt(x int) (pane focusPane, col int, ok bool) {
+	if x < 0 {
+		return paneContent, 0, false
+	}
+	if !v.contentOnly() {
+		sidebarW := v.sidebarWidth()
+		if x < sidebarW {
+			return paneSidebar, x, true
 		}
+		x -= sidebarW + 1 // Sidebar text and its right border
+	}
 
-		// Calculate clicked item index
-		clickedItemIndex := v.sidebarScrollOffset + clickedViewportLine
-		if clickedItemIndex >= 0 && clickedItemIndex < len(displayedIndices) {
-			v.sidebarCursor = clickedItemIndex
+	contentW := v.contentWidth()
+	if x >= 1 && x <= contentW {
+		return paneContent, x - 1, true // Past the content's left border
+	}
+	if v.contentOnly() {
+		return paneContent, 0, false
+	}
 
-			// Jump to the selected section in content
-			sectionIdx := displayedIndices[v.sidebarCursor]
-			section := v.content.Sections[sectionIdx]
-			v.scrollOffset = section.StartLine
-			v.contentCursor = 0 // Reset cursor to top of viewport
-			if v.scrollOffset < 0 {
-				v.scrollOffset = 0
-			}
-			// Switch to content pane after jumping so user can scroll
-			v.focusPane = paneContent
-		}
+	x -= contentW + 1
+	if x >= 1 && x <= v.sectionsPaneWidth() {
+		return paneSections, x - 1, true
+	}
+	return paneContent, 0, false
+}
 
-	} else if msg.X < sidebarW+contentW {
-		// --- Clicked in Content ---
-		v.focusPane = paneContent
-		if clickedViewportLine < v.viewportHeight() {
-			v.contentCursor = clickedViewportLine
-		}
+// clickSidebar selects the option on the given sidebar row and jumps the
+// content to it, keeping focus in the sidebar
+func (v *Viewer) clickSidebar(row int) {
+	if !v.hasOptions() {
+		return
+	}
+	v.focusPane = paneSidebar
 
-		// The rest of the logic from original handleMouseClick
-		contentX := msg.X - sidebarW - 2 // 2 for border and padding
-		clickedLineNum := v.scrollOffset + clickedViewportLine
-		if clickedLineNum >= len(v.content.Lines) {
-			return v, nil
-		}
-		clickedLine := v.content.Lines[clickedLineNum]
-
-		if option := v.extractOptionAtPosition(clickedLine, contentX); option != "" {
-			if sectionIdx := v.findSectionByOption(option); sectionIdx != -1 {
-				section := v.content.Sections[sectionIdx]
-				v.scrollOffset = section.StartLine
-				v.contentCursor = 0
-				if v.scrollOffset < 0 {
-					v.scrollOffset = 0
-				}
-
-				displayedIndices := v.getDisplayedSectionIndices()
-				for i, idx := range displayedIndices {
-					if idx == sectionIdx {
-						v.sidebarCursor = i
-						v.adjustSidebarScroll()
-						break
-					}
-				}
-			}
-		}
+	displayedIndices := v.getDisplayedSectionIndices()
+	idx := v.sidebarScrollOffset + row/v.sidebarRowsPerEntry()
+	if idx >= len(displayedIndices) {
+		return
+	}
+	v.sidebarCursor = idx
+	v.jumpToLine(v.content.Sections[displayedIndices[idx]].StartLine)
+}
 
-	} else {
-		// --- Clicked in Sections Pane ---
-		v.focusPane = paneSections
+// clickSections selects the man section on the given row of the sections
+// pane and jumps the content to it
+func (v *Viewer) clickSections(row int) {
+	v.focusPane = paneSections
+	rows := v.sectionsPaneRows()
+	idx := v.sectionsPaneScrollOffset + row
+	if idx >= len(rows) {
+		return
+	}
+	v.sectionsPaneCursor = idx
+	v.jumpToLine(v.rowSection(rows[idx]).StartLine)
+}
 
-		sections := v.content.ManSections
-		if len(sections) == 0 {
-			return v, nil
-		}
+// clickContent moves the cursor to the clicked line. Clicking a
+// name(section) reference opens that page; clicking an option flag jumps to
+// its definition and selects it in the sidebar.
+func (v *Viewer) clickContent(row, col int) tea.Cmd {
+	v.focusPane = paneContent
+	clickedLineNum := v.scrollOffset + row
+	if clickedLineNum >= len(v.lines()) {
+		return nil
+	}
+	v.contentCursor = row
 
-		// Calculate clicked item index
-		// The right pane is not scrollable, so index is just the line number
-		clickedItemIndex := clickedViewportLine
-		if clickedItemIndex >= 0 && clickedItemIndex < len(sections) {
-			v.sectionCursor = clickedItemIndex
+	// Lines are drawn after any centering margin and a two-column prefix
+	// ("  " or the "→ " match arrow)
+	contentX := v.columnOffset(v.lines()[clickedLineNum], col-v.contentMargin()-2)
+	if page, ok := refAt(v.lines()[clickedLineNum], contentX); ok {
+		return fetchPage(page, v.fetchOpts, "Opened "+page.Command())
+	}
+	if v.rawView {
+		// Raw lines don't line up with parsed option sections
+		return nil
+	}
 
-			// Jump to selected section
-			section := sections[v.sectionCursor]
-			v.scrollOffset = section.StartLine
-			v.contentCursor = 0 // Reset cursor to top of viewport
-			if v.scrollOffset < 0 {
-				v.scrollOffset = 0
-			}
-			// Switch to content pane after jumping
-			v.focusPane = paneContent
+	option := v.extractOptionAtPosition(v.lines()[clickedLineNum], contentX)
+	if option == "" {
+		return nil
+	}
+	sectionIdx := v.findSectionByOption(option)
+	if sectionIdx == -1 {
+		return nil
+	}
+	v.jumpToLine(v.content.Sections[sectionIdx].StartLine)
+	for i, idx := range v.getDisplayedSectionIndices() {
+		if idx == sectionIdx {
+			v.sidebarCursor = i
+			v.adjustSidebarScroll()
+			break
 		}
 	}
-
-	return v, nil
+	return nil
 }
 
 // extractOptionAtPosition attempts to extract an option (like "-r" or "--recursive")
@@ -1578,13 +3194,39 @@ func (v Viewer) overlayModal(background, modal string) string {
 // View implements tea.Model
 func (v Viewer) View() string {
 	if v.quitting {
-		return ""
+		if !v.inline {
+			return ""
+		}
+		// Leave the page in scrollback without prompts or overlays
+		v.mode = modeNormal
+		v.confirmingQuit = false
+		v.pendingQuickJump = false
+		v.statusMsg = ""
+	}
+	if width, height := v.minSize(); v.width < width || v.height < height {
+		return v.tooSmallView(width, height)
 	}
 
 	var b strings.Builder
 
 	// Title bar
 	title := fmt.Sprintf(" %s(%s) ", v.manPage.Name, v.manPage.Section)
+	if resolved := v.content.Section; resolved != "" {
+		// Show the section man actually resolved; note man -k's if it differs
+		title = fmt.Sprintf(" %s(%s) ", v.manPage.Name, resolved)
+		if v.manPage.Section != "" && v.manPage.Section != resolved {
+			title += "[listed as " + v.manPage.Section + "] "
+		}
+	}
+	if v.pinned {
+		title += "★ "
+	}
+	if v.rawView {
+		title += "[raw] "
+	}
+	if v.onResult() {
+		title += fmt.Sprintf("[result %d/%d] ", v.resultIndex+1, len(v.results))
+	}
 	if v.searchQuery != "" {
 		matchCount := v.totalMatches()
 		matchInfo := fmt.Sprintf(" [%d/%d matches] ", v.currentMatch+1, matchCount)
@@ -1601,6 +3243,14 @@ func (v Viewer) View() string {
 			searchPrefix = "desc:"
 		default:
 			searchPrefix = "search:"
+			if v.searchRegex != nil {
+				searchPrefix = "search(regex):"
+			} else if v.wholeWord {
+				searchPrefix = "search(word):"
+			}
+			if v.searchScope != nil {
+				searchPrefix = "search[" + v.searchScope.Name + "]:"
+			}
 		}
 		title += searchPrefix + " " + v.searchQuery + matchInfo
 	}
@@ -1613,20 +3263,35 @@ func (v Viewer) View() string {
 	b.WriteString(titleBar)
 	b.WriteString("\n")
 
-	// Three-column layout: sidebar + content + sections pane
-	sidebar := v.renderSidebar()
-	content := v.renderContent()
-	sectionsPane := v.renderSectionsPane()
-
-	mainArea := lipgloss.JoinHorizontal(lipgloss.Top, sidebar, content, sectionsPane)
+	// Three-column layout: sidebar + content + sections pane, or just the
+	// centered content in reader mode
+	var mainArea string
+	if v.mode == modeTable {
+		mainArea = v.renderOptionTable()
+	} else if v.rawView {
+		mainArea = v.renderContent()
+	} else if v.readerMode {
+		mainArea = lipgloss.NewStyle().MarginLeft(v.readerMargin()).Render(v.renderContent())
+	} else {
+		sidebar := v.renderSidebar()
+		content := v.renderContent()
+		sectionsPane := v.renderSectionsPane()
+		mainArea = lipgloss.JoinHorizontal(lipgloss.Top, sidebar, content, sectionsPane)
+	}
 
-	// Overlay modal if in section select or help mode
+	// Overlay modal if in section select, help, or outline mode
 	if v.mode == modeSectionSelect {
 		modal := v.renderSectionModal()
 		mainArea = v.overlayModal(mainArea, modal)
 	} else if v.mode == modeHelp {
 		modal := v.renderHelpModal()
 		mainArea = v.overlayModal(mainArea, modal)
+	} else if v.mode == modeOutline {
+		modal := v.renderOutlineModal()
+		mainArea = v.overlayModal(mainArea, modal)
+	} else if v.mode == modeSynopsis {
+		modal := v.renderSynopsisModal()
+		mainArea = v.overlayModal(mainArea, modal)
 	}
 
 	b.WriteString(mainArea)
@@ -1646,21 +3311,73 @@ func (v Viewer) View() string {
 			prefix = "d:"
 		default:
 			prefix = "/"
+			if v.searchScope != nil {
+				prefix = v.searchScope.Name + "/"
+			}
 		}
 		cmdLine = lipgloss.NewStyle().
 			Bold(true).
 			Foreground(lipgloss.Color("212")).
 			Render(prefix) + v.searchInput + "█"
+		cmdLine += helpStyle.Render("  o: O: d: / !regex w:word prefixes • \\ literal")
 	case modeNormal:
-		if v.searchQuery != "" {
-			cmdLine = helpStyle.Render("n next • N prev • esc clear • tab switch • G sections • ? help • q quit")
+		if v.confirmingQuit {
+			prompt := "Quit? (y/n)"
+			if v.backToSearch {
+				prompt = "Back to search? (y/n)"
+			}
+			cmdLine = lipgloss.NewStyle().
+				Bold(true).
+				Foreground(lipgloss.Color("212")).
+				Render(prompt)
+		} else if v.pendingQuickJump {
+			cmdLine = helpStyle.Render(quickJumpHint())
+		} else if v.statusMsg != "" {
+			cmdLine = v.statusMsg
+		} else if v.loading != "" {
+			cmdLine = helpStyle.Render("Loading " + v.loading + "…")
+		} else if v.searchQuery != "" {
+			help := "n next • N prev • esc clear • tab switch • G sections • ? help • q quit"
+			if v.searchScope != nil {
+				help = "in " + v.searchScope.Name + " • " + help
+			}
+			cmdLine = helpStyle.Render(help)
 		} else {
-			cmdLine = helpStyle.Render("tab switch • ↑↓ navigate • enter select • G sections • ? help • q quit")
+			// less-style position first; the hints give way on narrow terminals
+			help := helpStyle.Render("tab switch • ↑↓ navigate • enter select • G sections • ? help • q quit")
+			cmdLine = ansi.Truncate(v.positionStatus()+"  "+help, v.width, "…")
 		}
 	case modeSectionSelect:
-		cmdLine = helpStyle.Render("↑↓ navigate • enter jump • esc/G close")
+		if v.sectionJumper {
+			cmdLine = helpStyle.Render("type to filter • ↑↓ navigate • enter jump • esc close")
+		} else if v.sectionFiltering {
+			cmdLine = helpStyle.Render("type to filter • ↑↓ navigate • enter jump • esc clear filter")
+		} else {
+			cmdLine = helpStyle.Render("↑↓ navigate • g/G top/bottom • / filter • enter jump • esc close")
+		}
 	case modeHelp:
 		cmdLine = helpStyle.Render("Press ?, esc, or q to close")
+	case modeOutline:
+		cmdLine = helpStyle.Render("type to filter • ↑↓ navigate • enter jump • esc clear/close")
+	case modeSynopsis:
+		cmdLine = helpStyle.Render("↑↓ scroll • enter show in page • esc/S close")
+	case modeTable:
+		if v.tableFiltering {
+			cmdLine = lipgloss.NewStyle().
+				Bold(true).
+				Foreground(lipgloss.Color("212")).
+				Render("/") + v.tableFilter + "█"
+		} else {
+			cmdLine = helpStyle.Render("↑↓ navigate • / filter • enter show in page • esc/T close")
+		}
+	case modeCommand:
+		cmdLine = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("212")).
+			Render(":") + v.commandInput + "█"
+		if v.commandHint != "" {
+			cmdLine += "  " + helpStyle.Render(v.commandHint)
+		}
 	}
 	cmdLineBar := lipgloss.NewStyle().
 		Width(v.width).
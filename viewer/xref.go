@@ -0,0 +1,110 @@
+package viewer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shadyabhi/mantee/man/search"
+)
+
+// enterAction is what enter does in the content pane
+type enterAction int
+
+const (
+	enterFollow enterAction = iota // Open the name(section) reference on the cursor line
+	enterCopy                      // Copy the cursor line
+	enterNone                      // Nothing
+)
+
+// enterActionNames maps the config names to content pane enter actions
+var enterActionNames = map[string]enterAction{
+	"follow": enterFollow,
+	"copy":   enterCopy,
+	"none":   enterNone,
+}
+
+// ValidateEnterAction returns an error if name is not a known content pane
+// enter action ("follow", "copy", "none"). Empty means the default.
+func ValidateEnterAction(name string) error {
+	if _, ok := enterActionNames[name]; !ok && name != "" {
+		return fmt.Errorf("unknown enter action %q (want follow, copy, or none)", name)
+	}
+	return nil
+}
+
+// label describes the enter action for the help modal
+func (a enterAction) label() string {
+	switch a {
+	case enterCopy:
+		return "Copy the content line"
+	case enterNone:
+		return "Nothing in the content pane (enter_action)"
+	default:
+		return "Open the name(section) reference on the content line"
+	}
+}
+
+// pageRefRe matches a man page reference such as "ssh_config(5)" or
+// "git-log(1)" in page text
+var pageRefRe = regexp.MustCompile(`([A-Za-z0-9_][A-Za-z0-9_.:+-]*)\(([0-9n][a-zA-Z0-9]*)\)`)
+
+// pageRef is a man page reference found in a line of text
+type pageRef struct {
+	page       search.ManPage
+	start, end int // Byte offsets of the reference within the line
+}
+
+// pageRefs returns the man page references in line
+func pageRefs(line string) []pageRef {
+	var refs []pageRef
+	for _, m := range pageRefRe.FindAllStringSubmatchIndex(line, -1) {
+		page := search.ManPage{Name: line[m[2]:m[3]], Section: line[m[4]:m[5]]}
+		refs = append(refs, pageRef{page: page, start: m[0], end: m[1]})
+	}
+	return refs
+}
+
+// contentEnter runs the configured enter action on the cursor line
+func (v *Viewer) contentEnter() tea.Cmd {
+	lineIdx := v.scrollOffset + v.contentCursor
+	if lineIdx >= len(v.lines()) {
+		return nil
+	}
+	line := v.lines()[lineIdx]
+
+	switch v.enterAction {
+	case enterCopy:
+		return copyToClipboard(strings.TrimSpace(line), fmt.Sprintf("line %d", lineIdx+1))
+	case enterFollow:
+		return v.followRef(line)
+	}
+	return nil
+}
+
+// followRef opens the first page reference on line. Others on the same line
+// can be followed by clicking them.
+func (v *Viewer) followRef(line string) tea.Cmd {
+	refs := pageRefs(line)
+	if len(refs) == 0 {
+		v.statusMsg = "No name(section) reference on this line"
+		return nil
+	}
+	page := refs[0].page
+	status := "Opened " + page.Command()
+	if len(refs) > 1 {
+		status += fmt.Sprintf(" (the line has %d more; click one to open it)", len(refs)-1)
+	}
+	return fetchPage(page, v.fetchOpts, status)
+}
+
+// refAt returns the page reference covering byte offset x of line, if any
+func refAt(line string, x int) (search.ManPage, bool) {
+	for _, ref := range pageRefs(line) {
+		if x >= ref.start && x < ref.end {
+			return ref.page, true
+		}
+	}
+	return search.ManPage{}, false
+}